@@ -0,0 +1,216 @@
+package jquants
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// idTokenLifetime is how long an ID token issued by /token/auth_refresh stays valid.
+const idTokenLifetime = 24 * time.Hour
+
+// refreshTokenLifetime is how long a refresh token issued by /token/auth_user stays valid.
+const refreshTokenLifetime = 7 * 24 * time.Hour
+
+// tokenExpiryMargin is how long before expiry a token is renewed, so it does not expire in the middle of a
+// paginated fetch.
+const tokenExpiryMargin = 5 * time.Minute
+
+// tokenSource holds the credentials and tokens of the refresh-token/ID-token authentication flow.
+// mu is held while tokens are renewed, so concurrent requests wait for a single renewal.
+type tokenSource struct {
+	mu                 sync.Mutex
+	mailAddress        string
+	password           string
+	refreshToken       string
+	refreshTokenExpiry time.Time
+	idToken            string
+	idTokenExpiry      time.Time
+}
+
+// NewClientWithCredentials creates a client for [BaseURL] that authenticates with a J-Quants mail address and
+// password instead of an API key. Tokens are obtained on the first request (or by calling [Client.Authenticate])
+// and renewed automatically as they expire.
+func NewClientWithCredentials(mailAddress, password string, opts ...Option) *Client {
+	client := NewClient(BaseURL, "", opts...)
+	client.auth.mailAddress = mailAddress
+	client.auth.password = password
+	return client
+}
+
+// Authenticate obtains a refresh token for mailAddress and password from /token/auth_user and exchanges it
+// for an ID token at /token/auth_refresh. Subsequent requests send the ID token as a bearer token instead of
+// the API key. The credentials are kept so that the refresh token, which expires after about a week, can be
+// re-issued; the ID token expires after about 24 hours and is renewed before the request that would use it.
+func (c *Client) Authenticate(ctx context.Context, mailAddress, password string) error {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	c.auth.mailAddress = mailAddress
+	c.auth.password = password
+	c.auth.refreshToken = ""
+	c.auth.idToken = ""
+	return c.renewTokens(ctx)
+}
+
+// requestAuth returns the header name and value that authenticate a request. A credential set with
+// ContextWithIDToken or ContextWithAPIKey takes precedence; otherwise a client with credentials sends its
+// ID token, renewing it first if it is about to expire, and any other client sends its API key.
+func (c *Client) requestAuth(ctx context.Context) (string, string, error) {
+	if header, value, ok := contextCredential(ctx); ok {
+		return header, value, nil
+	}
+	idToken, err := c.requestIDToken(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if idToken != "" {
+		return "Authorization", "Bearer " + idToken, nil
+	}
+	apiKey, err := c.requestAPIKey(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return "x-api-key", apiKey, nil
+}
+
+// requestIDToken returns a valid ID token, renewing the tokens if needed.
+// It returns an empty token if the client has no credentials.
+func (c *Client) requestIDToken(ctx context.Context) (string, error) {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	if c.auth.mailAddress == "" && c.auth.refreshToken == "" {
+		return "", nil
+	}
+	if c.auth.idToken != "" && time.Now().Before(c.auth.idTokenExpiry.Add(-tokenExpiryMargin)) {
+		return c.auth.idToken, nil
+	}
+	if err := c.renewTokens(ctx); err != nil {
+		return "", err
+	}
+	return c.auth.idToken, nil
+}
+
+// renewIDToken renews the ID token after the API rejected the bearer header value `rejected` with 401, and
+// returns the new header value. If another request already renewed the token, the current one is returned
+// without contacting the token endpoints again.
+func (c *Client) renewIDToken(ctx context.Context, rejected string) (string, error) {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	if c.auth.idToken != "" && "Bearer "+c.auth.idToken != rejected {
+		return "Bearer " + c.auth.idToken, nil
+	}
+	c.auth.idToken = ""
+	if err := c.renewTokens(ctx); err != nil {
+		return "", fmt.Errorf("failed to renew ID token after 401: %w", err)
+	}
+	return "Bearer " + c.auth.idToken, nil
+}
+
+// RefreshToken returns the current refresh token, or an empty string if the client does not use token
+// authentication or has not authenticated yet.
+func (c *Client) RefreshToken() string {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	return c.auth.refreshToken
+}
+
+// IDTokenExpiry returns when the current ID token expires, or the zero time if there is none.
+// The token is renewed shortly before this time, or sooner if the API rejects it with 401.
+func (c *Client) IDTokenExpiry() time.Time {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	if c.auth.idToken == "" {
+		return time.Time{}
+	}
+	return c.auth.idTokenExpiry
+}
+
+// renewTokens fetches a new ID token, first fetching a new refresh token if there is none or it is about to
+// expire. The caller must hold c.auth.mu.
+func (c *Client) renewTokens(ctx context.Context) error {
+	now := time.Now()
+	if c.auth.refreshToken == "" || !now.Before(c.auth.refreshTokenExpiry.Add(-tokenExpiryMargin)) {
+		if c.auth.mailAddress == "" {
+			return errors.New("refresh token expired and no credentials to re-issue it")
+		}
+		var r struct {
+			RefreshToken string `json:"refreshToken"`
+		}
+		body := map[string]string{"mailaddress": c.auth.mailAddress, "password": c.auth.password}
+		if err := c.postToken(ctx, "/token/auth_user", nil, body, &r); err != nil {
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+		if r.RefreshToken == "" {
+			return errors.New("failed to get refresh token: empty token in response")
+		}
+		c.auth.refreshToken = r.RefreshToken
+		c.auth.refreshTokenExpiry = now.Add(refreshTokenLifetime)
+	}
+	var r struct {
+		IDToken string `json:"idToken"`
+	}
+	query := url.Values{"refreshtoken": {c.auth.refreshToken}}
+	if err := c.postToken(ctx, "/token/auth_refresh", query, nil, &r); err != nil {
+		return fmt.Errorf("failed to get ID token: %w", err)
+	}
+	if r.IDToken == "" {
+		return errors.New("failed to get ID token: empty token in response")
+	}
+	c.auth.idToken = r.IDToken
+	c.auth.idTokenExpiry = now.Add(idTokenLifetime)
+	return nil
+}
+
+// tokenBaseURL returns the base URL of the token endpoints (see [WithAuthBaseURL]).
+func (c *Client) tokenBaseURL() string {
+	switch {
+	case c.authBaseURL != "":
+		return c.authBaseURL
+	case c.baseURL == BaseURL:
+		return AuthBaseURL
+	default:
+		return c.baseURL
+	}
+}
+
+// postToken sends a POST request to a token endpoint and decodes the response into out.
+// body, if not nil, is sent as JSON.
+func (c *Client) postToken(ctx context.Context, urlPath string, query url.Values, body any, out any) error {
+	u, err := url.Parse(c.tokenBaseURL() + urlPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	u.RawQuery = query.Encode()
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept-Encoding", "gzip")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send POST request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, out); err != nil {
+		return fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package jquants
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var authUser, authRefresh int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token/auth_user":
+			authUser++
+			var body struct {
+				MailAddress string `json:"mailaddress"`
+				Password    string `json:"password"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Method != "POST" || body.MailAddress != "user@example.com" || body.Password != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"message":"invalid credentials"}`)
+				return
+			}
+			fmt.Fprint(w, `{"refreshToken":"refresh"}`)
+		case "/token/auth_refresh":
+			authRefresh++
+			if r.URL.Query().Get("refreshtoken") != "refresh" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"message":"invalid refresh token"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"idToken":"id-%d"}`, authRefresh)
+		default:
+			if r.Header.Get("Authorization") != fmt.Sprintf("Bearer id-%d", authRefresh) || r.Header.Get("x-api-key") != "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message":"unauthorized"}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	if err := client.Authenticate(t.Context(), "user@example.com", "wrong"); err == nil {
+		t.Error("Expected error for invalid credentials")
+	}
+	if err := client.Authenticate(t.Context(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	code := "13010"
+	if _, err := client.StockPrice(t.Context(), StockPriceRequest{Code: &code}); err != nil {
+		t.Fatalf("Failed to get stock price with ID token: %v", err)
+	}
+	if authUser != 2 || authRefresh != 1 {
+		t.Errorf("Unexpected token requests: auth_user=%d, auth_refresh=%d", authUser, authRefresh)
+	}
+
+	client.auth.idTokenExpiry = time.Now()
+	if _, err := client.StockPrice(t.Context(), StockPriceRequest{Code: &code}); err != nil {
+		t.Fatalf("Failed to get stock price after ID token expiry: %v", err)
+	}
+	if authUser != 2 || authRefresh != 2 {
+		t.Errorf("Expected only the ID token to be renewed: auth_user=%d, auth_refresh=%d", authUser, authRefresh)
+	}
+
+	client.auth.idTokenExpiry = time.Now()
+	client.auth.refreshTokenExpiry = time.Now()
+	if _, err := client.StockPrice(t.Context(), StockPriceRequest{Code: &code}); err != nil {
+		t.Fatalf("Failed to get stock price after refresh token expiry: %v", err)
+	}
+	if authUser != 3 || authRefresh != 3 {
+		t.Errorf("Expected both tokens to be renewed: auth_user=%d, auth_refresh=%d", authUser, authRefresh)
+	}
+}
+
+func TestAuthenticate_RenewOnUnauthorized(t *testing.T) {
+	var authRefresh, rejected int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token/auth_user":
+			fmt.Fprint(w, `{"refreshToken":"refresh"}`)
+		case "/token/auth_refresh":
+			authRefresh++
+			fmt.Fprintf(w, `{"idToken":"id-%d"}`, authRefresh)
+		default:
+			if r.Header.Get("Authorization") == "Bearer id-1" || r.URL.Query().Get("code") == "99990" {
+				rejected++
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message":"The incoming token is invalid or expired."}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	if err := client.Authenticate(t.Context(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	code := "13010"
+	if _, err := client.StockPrice(t.Context(), StockPriceRequest{Code: &code}); err != nil {
+		t.Fatalf("Expected the request to succeed after renewing the ID token: %v", err)
+	}
+	if authRefresh != 2 || rejected != 1 {
+		t.Errorf("Unexpected requests: auth_refresh=%d, rejected=%d", authRefresh, rejected)
+	}
+	if client.RefreshToken() != "refresh" || !client.IDTokenExpiry().After(time.Now()) {
+		t.Errorf("Unexpected token state: refresh=%q, expiry=%v", client.RefreshToken(), client.IDTokenExpiry())
+	}
+
+	code = "99990"
+	_, err := client.StockPrice(t.Context(), StockPriceRequest{Code: &code})
+	var unauthorized Unauthorized
+	if !errors.As(err, &unauthorized) {
+		t.Errorf("Expected Unauthorized after one renewal, got %v", err)
+	}
+	if authRefresh != 3 || rejected != 3 {
+		t.Errorf("Expected a single renewal and retry: auth_refresh=%d, rejected=%d", authRefresh, rejected)
+	}
+}
+
+type recordingTransport struct {
+	urls []string
+}
+
+func (t *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, r.URL.String())
+	body := `{"refreshToken":"refresh"}`
+	if r.URL.Path == "/v1/token/auth_refresh" {
+		body = `{"idToken":"id"}`
+	}
+	return &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+}
+
+func TestAuthenticate_DefaultAuthBaseURL(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewClientWithCredentials("user@example.com", "secret", WithHTTPClient(&http.Client{Transport: transport}))
+	if err := client.Authenticate(t.Context(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	want := []string{
+		"https://api.jquants.com/v1/token/auth_user",
+		"https://api.jquants.com/v1/token/auth_refresh?refreshtoken=refresh",
+	}
+	if !slices.Equal(transport.urls, want) {
+		t.Errorf("Unexpected token URLs: got %v, want %v", transport.urls, want)
+	}
+	client = NewClient(BaseURL, "", WithAuthBaseURL("https://auth.example.com/v1"))
+	if got := client.tokenBaseURL(); got != "https://auth.example.com/v1" {
+		t.Errorf("Unexpected overridden token base URL: %s", got)
+	}
+}
@@ -0,0 +1,327 @@
+package jquants
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/s-shiga/jquants-go/v2/codes"
+)
+
+// fetchBatch runs fetch concurrently for each key and collects the results into a map keyed by key.
+// At most c.concurrency() fetches run at the same time, and new fetches also wait for the client's in-flight byte limit.
+// Goroutine launches are spaced by a random delay of up to the client's startJitter so that requests
+// are spread across the rate window instead of arriving as a single burst.
+// Results for keys that succeeded are returned together with the joined per-key errors.
+func fetchBatch[T any](
+	ctx context.Context,
+	c *Client,
+	keys []string,
+	fetch func(ctx context.Context, key string) ([]T, error),
+) (map[string][]T, error) {
+	return fetchBatchN(ctx, c, keys, c.concurrency(), fetch)
+}
+
+// fetchBatchN is fetchBatch with an explicit bound on the number of concurrent fetches.
+func fetchBatchN[T any](
+	ctx context.Context,
+	c *Client,
+	keys []string,
+	concurrency int,
+	fetch func(ctx context.Context, key string) ([]T, error),
+) (map[string][]T, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string][]T, len(keys))
+		errs    []error
+	)
+	sem := make(chan struct{}, max(1, concurrency))
+	for i, key := range keys {
+		if i > 0 && c.startJitter > 0 {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return results, errors.Join(append(errs, ctx.Err())...)
+			case <-time.After(rand.N(c.startJitter)):
+			}
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return results, errors.Join(append(errs, ctx.Err())...)
+		case sem <- struct{}{}:
+		}
+		if c.inFlightLimit != nil {
+			if err := c.inFlightLimit.wait(ctx); err != nil {
+				<-sem
+				wg.Wait()
+				return results, errors.Join(append(errs, err)...)
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ctx := ctx
+			if c.inFlightLimit != nil {
+				reservation := &inFlightReservation{limit: c.inFlightLimit}
+				defer reservation.release()
+				ctx = context.WithValue(ctx, inFlightReservationContextKey{}, reservation)
+			}
+			data, err := fetch(ctx, key)
+			mu.Lock()
+			defer mu.Unlock()
+			if data != nil {
+				results[key] = data
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}()
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+// inFlightLimit limits the bytes of response bodies read by the batch fetches that are still running.
+// A fetch's bytes are released when it returns, so results already handed back to the batch are not counted.
+type inFlightLimit struct {
+	mu      sync.Mutex
+	limit   int64
+	used    int64
+	changed chan struct{} // closed and replaced whenever used decreases
+}
+
+func newInFlightLimit(limit int64) *inFlightLimit {
+	return &inFlightLimit{limit: limit, changed: make(chan struct{})}
+}
+
+// wait blocks until the limit has room or ctx is done. A fetch always proceeds when nothing is in flight,
+// so a limit smaller than a single response cannot block the batch forever.
+func (b *inFlightLimit) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		if b.used == 0 || b.used < b.limit {
+			b.mu.Unlock()
+			return nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *inFlightLimit) add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used += n
+}
+
+func (b *inFlightLimit) release(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+type inFlightReservationContextKey struct{}
+
+// inFlightReservation records the bytes a single running batch fetch has charged to an inFlightLimit.
+type inFlightReservation struct {
+	limit *inFlightLimit
+	n     atomic.Int64
+}
+
+func (r *inFlightReservation) add(n int) {
+	r.n.Add(int64(n))
+	r.limit.add(int64(n))
+}
+
+func (r *inFlightReservation) release() {
+	r.limit.release(r.n.Swap(0))
+}
+
+// chargeInFlight charges n bytes to the in-flight reservation carried by ctx, if any.
+func chargeInFlight(ctx context.Context, n int) {
+	if r, ok := ctx.Value(inFlightReservationContextKey{}).(*inFlightReservation); ok {
+		r.add(n)
+	}
+}
+
+// trackBody makes resp.Body charge the bytes read to the in-flight reservation carried by ctx, if any.
+// The body is decompressed first so the decoded size is counted.
+func trackBody(ctx context.Context, resp *http.Response) error {
+	r, ok := ctx.Value(inFlightReservationContextKey{}).(*inFlightReservation)
+	if !ok {
+		return nil
+	}
+	body, err := responseReader(resp)
+	if err != nil {
+		_ = resp.Body.Close()
+		return err
+	}
+	resp.Body = countingBody{Reader: body, closers: []io.Closer{body, resp.Body}, reservation: r}
+	resp.Header.Del("Content-Encoding")
+	return nil
+}
+
+type countingBody struct {
+	io.Reader
+	closers     []io.Closer
+	reservation *inFlightReservation
+}
+
+func (b countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.reservation.add(n)
+	return n, err
+}
+
+func (b countingBody) Close() error {
+	var errs []error
+	for _, c := range b.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NormalizeCodes cleans a user-supplied list of security codes with [codes.NormalizeCode]: it trims whitespace,
+// uppercases alphabetic characters (e.g., "130a" becomes "130A"), pads 4-character codes to the API's 5-character
+// form by appending "0", and drops blanks and duplicates while preserving the order of first occurrence.
+// Codes that NormalizeCode rejects are left out of the result and reported in the joined error.
+func NormalizeCodes(list []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(list))
+	normalized := make([]string, 0, len(list))
+	var errs []error
+	for _, code := range list {
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		code, err := codes.NormalizeCode(code)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		normalized = append(normalized, code)
+	}
+	return normalized, errors.Join(errs...)
+}
+
+// StockPrices retrieves daily stock prices for several codes concurrently and returns them keyed by code.
+// req.Code and req.Date are ignored; req.From and req.To apply to every code.
+// codes are cleaned with NormalizeCodes, so the result is keyed by the normalized codes.
+// If some codes are invalid or fail, the prices for the remaining codes are returned along with the joined errors.
+func (c *Client) StockPrices(ctx context.Context, codes []string, req StockPriceRequest) (map[string][]StockPrice, error) {
+	normalized, invalid := NormalizeCodes(codes)
+	prices, err := fetchBatch(ctx, c, normalized, func(ctx context.Context, code string) ([]StockPrice, error) {
+		r := req
+		r.Code, r.Date = &code, nil
+		return c.StockPrice(ctx, r)
+	})
+	return prices, errors.Join(invalid, err)
+}
+
+// FinancialStatementsMulti retrieves the financial statements of several codes concurrently, like
+// [Client.StockPrices], and returns them keyed by code for cross-sectional screens.
+// codes are cleaned with NormalizeCodes, so the result is keyed by the normalized codes.
+// If some codes are invalid or fail, the statements for the remaining codes are returned along with the joined errors.
+func (c *Client) FinancialStatementsMulti(ctx context.Context, codes []string) (map[string][]FinancialStatement, error) {
+	normalized, invalid := NormalizeCodes(codes)
+	statements, err := fetchBatch(ctx, c, normalized, func(ctx context.Context, code string) ([]FinancialStatement, error) {
+		return c.FinancialStatements(ctx, FinancialStatementsRequest{Code: &code})
+	})
+	return statements, errors.Join(invalid, err)
+}
+
+// StockPriceConcurrent retrieves daily stock prices like [Client.StockPrice], but splits [req.From, req.To] into
+// workers sub-ranges of about equal length and fetches them concurrently, each with its own pagination.
+// This cuts the wall-clock time of multi-year pulls; the client's rate limiter still bounds the total request
+// rate across all workers. The merged result is sorted by code and then date.
+// Requests with a Date or without both From and To, and workers below 2, are fetched with StockPrice.
+// If some sub-ranges fail, the prices for the remaining ones are returned along with the joined errors.
+func (c *Client) StockPriceConcurrent(ctx context.Context, req StockPriceRequest, workers int) ([]StockPrice, error) {
+	if req.Date != nil || req.From == nil || req.To == nil || workers < 2 {
+		return c.StockPrice(ctx, req)
+	}
+	ranges, err := splitDateRange(*req.From, *req.To, workers)
+	if err != nil {
+		return nil, err
+	}
+	starts := slices.Sorted(maps.Keys(ranges))
+	results, err := fetchBatchN(ctx, c, starts, workers, func(ctx context.Context, from string) ([]StockPrice, error) {
+		to := ranges[from]
+		chunk := req
+		chunk.From, chunk.To = &from, &to
+		return c.StockPrice(ctx, chunk)
+	})
+	data := make([]StockPrice, 0)
+	for _, from := range starts {
+		data = append(data, results[from]...)
+	}
+	slices.SortStableFunc(data, func(a, b StockPrice) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), cmp.Compare(a.Date, b.Date))
+	})
+	return data, err
+}
+
+// splitDateRange splits [from, to] (YYYY-MM-DD) into at most n contiguous sub-ranges of about equal length,
+// returned as a map from each sub-range's first date to its last date.
+func splitDateRange(from, to string, n int) (map[string]string, error) {
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse from date: %w", err)
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse to date: %w", err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("from date %s is after to date %s", from, to)
+	}
+	days := int(end.Sub(start).Hours()/24) + 1
+	size := (days + n - 1) / n
+	ranges := make(map[string]string, n)
+	for !start.After(end) {
+		last := start.AddDate(0, 0, size-1)
+		if last.After(end) {
+			last = end
+		}
+		ranges[start.Format(dateLayout)] = last.Format(dateLayout)
+		start = last.AddDate(0, 0, 1)
+	}
+	return ranges, nil
+}
+
+// IndexOptionPriceRange retrieves Nikkei 225 option snapshots for every trading day in [from, to] (YYYY-MM-DD)
+// concurrently and returns them keyed by date. Non-trading days are skipped using the trading calendar.
+// If some dates fail, the snapshots for the remaining dates are returned along with the joined errors.
+func (c *Client) IndexOptionPriceRange(ctx context.Context, from, to string) (map[string][]IndexOptionPrice, error) {
+	entries, err := c.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading calendar: %w", err)
+	}
+	dates := NewCalendar(entries).TradingDays(from, to)
+	return fetchBatch(ctx, c, dates, func(ctx context.Context, date string) ([]IndexOptionPrice, error) {
+		return c.IndexOptionPrice(ctx, IndexOptionPriceRequest{Date: date})
+	})
+}
@@ -0,0 +1,157 @@
+package jquants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNormalizeCodes(t *testing.T) {
+	got, err := NormalizeCodes([]string{" 7203", "72030", "", "130a", "6758 ", "  ", "130A0"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"72030", "130A0", "67580"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Unexpected normalized codes: got %v, want %v", got, want)
+	}
+	got, err = NormalizeCodes([]string{"12", "7203", "72-30"})
+	if err == nil || !strings.Contains(err.Error(), `"12"`) || !strings.Contains(err.Error(), `"72-30"`) {
+		t.Errorf("Expected the invalid codes to be reported: %v", err)
+	}
+	if !slices.Equal(got, []string{"72030"}) {
+		t.Errorf("Expected only the valid code to be kept: %v", got)
+	}
+}
+
+func TestFinancialStatementsMulti(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := r.URL.Query().Get("code")
+		if code == "99990" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"invalid code"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"DiscDate":"2024-05-08","Code":%q,"CurPerType":"FY"}]}`, code)
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil)
+	statements, err := client.FinancialStatementsMulti(t.Context(), []string{"7203", "67580", "72030", "12", "99990"})
+	if err == nil || !strings.Contains(err.Error(), `"12"`) || !strings.Contains(err.Error(), "99990") {
+		t.Errorf("Expected the invalid and failed codes to be reported: %v", err)
+	}
+	keys := slices.Sorted(maps.Keys(statements))
+	if !slices.Equal(keys, []string{"67580", "72030"}) {
+		t.Fatalf("Unexpected codes: %v", keys)
+	}
+	for code, s := range statements {
+		if len(s) != 1 || s[0].Code != code {
+			t.Errorf("Unexpected statements for %s: %+v", code, s)
+		}
+	}
+}
+
+func TestInFlightLimit(t *testing.T) {
+	limit := newInFlightLimit(10)
+	r := &inFlightReservation{limit: limit}
+	r.add(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limit.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected wait to block on an exhausted limit, got %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- limit.wait(context.Background()) }()
+	r.release()
+	if err := <-done; err != nil {
+		t.Fatalf("Expected wait to return after release, got %v", err)
+	}
+	if limit.used != 0 {
+		t.Errorf("Unexpected bytes in use after release: %d", limit.used)
+	}
+}
+
+func TestWithMaxInFlightBytes_Zero(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"DiscDate":"2024-05-08","Code":%q,"CurPerType":"FY"}]}`, r.URL.Query().Get("code"))
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil, WithMaxInFlightBytes(0))
+	if client.inFlightLimit != nil {
+		t.Errorf("Expected a zero limit to disable the in-flight limit")
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	statements, err := client.FinancialStatementsMulti(ctx, []string{"72030", "67580"})
+	if err != nil {
+		t.Fatalf("Failed to fetch financial statements: %v", err)
+	}
+	if len(statements) != 2 {
+		t.Errorf("Unexpected number of codes: %d", len(statements))
+	}
+}
+
+func TestInFlightLimit_Zero(t *testing.T) {
+	limit := newInFlightLimit(0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := limit.wait(ctx); err != nil {
+		t.Fatalf("Expected the first fetch to proceed with nothing in flight, got %v", err)
+	}
+}
+
+func TestSplitDateRange(t *testing.T) {
+	got, err := splitDateRange("2024-01-01", "2024-01-10", 3)
+	if err != nil {
+		t.Fatalf("Failed to split date range: %v", err)
+	}
+	want := map[string]string{"2024-01-01": "2024-01-04", "2024-01-05": "2024-01-08", "2024-01-09": "2024-01-10"}
+	if !maps.Equal(got, want) {
+		t.Errorf("Unexpected sub-ranges: got %v, want %v", got, want)
+	}
+	if got, _ := splitDateRange("2024-01-01", "2024-01-02", 5); len(got) != 2 {
+		t.Errorf("Expected one sub-range per day when workers exceed days, got %v", got)
+	}
+	if _, err := splitDateRange("2024-01-10", "2024-01-01", 2); err == nil {
+		t.Error("Expected error for a reversed range")
+	}
+}
+
+func TestStockPriceConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		mu.Lock()
+		ranges = append(ranges, from+"~"+to)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"data":[{"Date":%q,"Code":"72030","UL":"0","LL":"0"},{"Date":%q,"Code":"13010","UL":"0","LL":"0"}]}`, to, from)
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil)
+	code, from, to := "72030", "2024-01-01", "2024-01-10"
+	prices, err := client.StockPriceConcurrent(t.Context(), StockPriceRequest{Code: &code, From: &from, To: &to}, 2)
+	if err != nil {
+		t.Fatalf("Failed to get stock prices: %v", err)
+	}
+	slices.Sort(ranges)
+	if !slices.Equal(ranges, []string{"2024-01-01~2024-01-05", "2024-01-06~2024-01-10"}) {
+		t.Errorf("Unexpected sub-range requests: %v", ranges)
+	}
+	var keys []string
+	for _, p := range prices {
+		keys = append(keys, p.Code+" "+p.Date)
+	}
+	want := []string{"13010 2024-01-01", "13010 2024-01-06", "72030 2024-01-05", "72030 2024-01-10"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("Unexpected merge order: got %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,421 @@
+package jquants
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API responses keyed by the full request URL. Requests authenticated with
+// [ContextWithAPIKey] or [ContextWithIDToken] add a hash of that credential to the key.
+// When a cache is configured with [WithCache], the client consults it before sending a request
+// and stores every successful response in it. The stored bytes are the uncompressed JSON response body,
+// so cached data still flows through the normal unmarshalers.
+type Cache interface {
+	// Get returns the cached body for key and whether it was found and has not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores body under key for ttl. A ttl of zero or less means the entry does not expire.
+	// The client passes the TTLs configured with [WithCacheTTL]: historical responses never expire by default.
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// defaultMemoryCacheEntries is the capacity of a MemoryCache created with a non-positive maxEntries.
+const defaultMemoryCacheEntries = 1024
+
+// MemoryCache is an in-memory [Cache] that evicts the least recently used response once it holds
+// maxEntries responses. It is safe for concurrent use.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is most recently used
+	entries    map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key     string
+	body    []byte
+	expires time.Time // zero if the entry does not expire
+}
+
+// NewMemoryCache creates a MemoryCache holding at most maxEntries responses (1024 if maxEntries <= 0).
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryCacheEntries
+	}
+	return &MemoryCache{maxEntries: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (mc *MemoryCache) Get(key string) ([]byte, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	elem, ok := mc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		mc.order.Remove(elem)
+		delete(mc.entries, key)
+		return nil, false
+	}
+	mc.order.MoveToFront(elem)
+	return entry.body, true
+}
+
+func (mc *MemoryCache) Set(key string, body []byte, ttl time.Duration) {
+	entry := &memoryCacheEntry{key: key, body: body}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if elem, ok := mc.entries[key]; ok {
+		elem.Value = entry
+		mc.order.MoveToFront(elem)
+		return
+	}
+	mc.entries[key] = mc.order.PushFront(entry)
+	for mc.order.Len() > mc.maxEntries {
+		oldest := mc.order.Back()
+		mc.order.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted.
+func (mc *MemoryCache) Len() int {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.order.Len()
+}
+
+// Codec converts cached response bodies (uncompressed JSON) to and from their stored representation.
+// It lets a [FileCache] trade disk space for decode speed.
+type Codec interface {
+	// Encode converts a JSON body into its stored form.
+	Encode(body []byte) ([]byte, error)
+	// Decode converts a stored form back into the JSON body.
+	Decode(data []byte) ([]byte, error)
+	// Extension is the file name extension of the stored form (e.g., ".json.gz").
+	Extension() string
+}
+
+// GzipCodec stores bodies as gzip-compressed JSON. It is the default codec of FileCache.
+type GzipCodec struct{}
+
+func (GzipCodec) Encode(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GzipCodec) Decode(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (GzipCodec) Extension() string { return ".json.gz" }
+
+// JSONCodec stores bodies as uncompressed JSON: larger on disk, but read back without decompression.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(body []byte) ([]byte, error) { return body, nil }
+func (JSONCodec) Decode(data []byte) ([]byte, error) { return data, nil }
+func (JSONCodec) Extension() string                  { return ".json" }
+
+// fileCacheManifest is the name of the file in which a FileCache records the expiry, size and last use
+// of each entry.
+const fileCacheManifest = "manifest.json"
+
+// FileCache is a [Cache] that stores each response in its own file under a directory, so it persists
+// between runs. File names are the SHA-256 hash of the request URL plus the codec's extension.
+// A manifest in the same directory records when each entry expires and when it was last used; files
+// without a manifest entry, such as those written by earlier versions, never expire.
+// With [FileCache.SetMaxSize], the least recently used entries are evicted once the stored files exceed
+// the cap. Reads update the last use in memory only; call [FileCache.Flush] before exiting so the next run
+// evicts by them. It is safe for concurrent use by the clients of one process.
+type FileCache struct {
+	dir    string
+	codec  Codec
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	entries  map[string]fileCacheEntry // keyed by file name
+	size     int64
+	maxSize  int64
+	modified bool // entries changed since the manifest was last written
+}
+
+type fileCacheEntry struct {
+	// Expires is when the entry expires; zero if it does not.
+	Expires time.Time `json:"expires,omitzero"`
+	// Size is the size of the stored file in bytes.
+	Size int64 `json:"size"`
+	// Used is when the entry was last stored or read.
+	Used time.Time `json:"used"`
+}
+
+// NewFileCache creates a FileCache rooted at dir, creating the directory if needed.
+// Responses are stored with [GzipCodec].
+func NewFileCache(dir string) (*FileCache, error) {
+	return NewFileCacheWithCodec(dir, GzipCodec{})
+}
+
+// NewFileCacheWithCodec creates a FileCache rooted at dir that stores responses with codec.
+// It loads the manifest left by earlier runs, if any.
+func NewFileCacheWithCodec(dir string, codec Codec) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	fc := &FileCache{dir: dir, codec: codec, entries: make(map[string]fileCacheEntry)}
+	if err := fc.load(); err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
+
+// load reads the manifest and adds the cache files it does not list, dropping entries whose files are gone.
+func (fc *FileCache) load() error {
+	data, err := os.ReadFile(filepath.Join(fc.dir, fileCacheManifest))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read cache manifest: %w", err)
+	}
+	manifest := make(map[string]fileCacheEntry)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return fmt.Errorf("failed to decode cache manifest: %w", err)
+		}
+	}
+	files, err := os.ReadDir(fc.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || name == fileCacheManifest || !strings.HasSuffix(name, fc.codec.Extension()) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry, ok := manifest[name]
+		if !ok {
+			entry = fileCacheEntry{Used: info.ModTime()}
+		}
+		entry.Size = info.Size()
+		fc.entries[name] = entry
+		fc.size += entry.Size
+	}
+	fc.modified = len(fc.entries) != len(manifest)
+	return nil
+}
+
+// SetLogger sets the logger that receives failures to read or write cache files. By default a FileCache logs
+// to the logger of the client it is passed to with [WithCache], or to slog.Default() outside a client.
+// A nil logger silences them.
+func (fc *FileCache) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	fc.logger = logger
+}
+
+// setDefaultLogger is called by NewClient so the cache logs to the client's logger unless SetLogger was called.
+func (fc *FileCache) setDefaultLogger(logger *slog.Logger) {
+	if fc.logger == nil {
+		fc.logger = logger
+	}
+}
+
+func (fc *FileCache) log() *slog.Logger {
+	if fc.logger == nil {
+		return slog.Default()
+	}
+	return fc.logger
+}
+
+// SetMaxSize caps the total size of the stored files at maxBytes, evicting the least recently used entries
+// as needed, starting immediately. Zero or less removes the cap, which is the default.
+func (fc *FileCache) SetMaxSize(maxBytes int64) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.maxSize = maxBytes
+	fc.evict()
+	fc.saveManifest()
+}
+
+// Size returns the total size in bytes of the stored files.
+func (fc *FileCache) Size() int64 {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.size
+}
+
+func (fc *FileCache) name(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + fc.codec.Extension()
+}
+
+func (fc *FileCache) Get(key string) ([]byte, bool) {
+	name := fc.name(key)
+	fc.mu.Lock()
+	entry, ok := fc.entries[name]
+	if ok && !entry.Expires.IsZero() && !time.Now().Before(entry.Expires) {
+		fc.remove(name)
+		fc.saveManifest()
+		ok = false
+	}
+	fc.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(fc.dir, name))
+	if err != nil {
+		return nil, false
+	}
+	body, err := fc.codec.Decode(data)
+	if err != nil {
+		fc.log().Warn("failed to decode cache file", "error", err)
+		return nil, false
+	}
+	fc.mu.Lock()
+	if entry, ok := fc.entries[name]; ok {
+		// The new access time is written by Flush or with the next change to the manifest.
+		entry.Used = time.Now()
+		fc.entries[name] = entry
+		fc.modified = true
+	}
+	fc.mu.Unlock()
+	return body, true
+}
+
+func (fc *FileCache) Set(key string, body []byte, ttl time.Duration) {
+	data, err := fc.codec.Encode(body)
+	if err != nil {
+		fc.log().Warn("failed to encode cache file", "error", err)
+		return
+	}
+	name := fc.name(key)
+	if err := fc.writeFile(name, data); err != nil {
+		fc.log().Warn("failed to store cache file", "error", err)
+		return
+	}
+	now := time.Now()
+	entry := fileCacheEntry{Size: int64(len(data)), Used: now}
+	if ttl > 0 {
+		entry.Expires = now.Add(ttl)
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.size += entry.Size - fc.entries[name].Size
+	fc.entries[name] = entry
+	fc.modified = true
+	fc.evict()
+	fc.saveManifest()
+}
+
+// Flush writes the manifest if entries changed since it was last written, such as the last-use times
+// recorded by Get, and returns the error if it cannot be written.
+func (fc *FileCache) Flush() error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.saveManifest()
+}
+
+// evict removes expired entries and then the least recently used ones until the size fits the cap.
+// The caller must hold fc.mu.
+func (fc *FileCache) evict() {
+	if fc.maxSize <= 0 || fc.size <= fc.maxSize {
+		return
+	}
+	now := time.Now()
+	names := make([]string, 0, len(fc.entries))
+	for name, entry := range fc.entries {
+		if !entry.Expires.IsZero() && !now.Before(entry.Expires) {
+			fc.remove(name)
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int { return fc.entries[a].Used.Compare(fc.entries[b].Used) })
+	for _, name := range names {
+		if fc.size <= fc.maxSize {
+			break
+		}
+		fc.remove(name)
+	}
+}
+
+// remove deletes the file of an entry and drops it from the manifest. The caller must hold fc.mu.
+func (fc *FileCache) remove(name string) {
+	if err := os.Remove(filepath.Join(fc.dir, name)); err != nil && !os.IsNotExist(err) {
+		fc.log().Warn("failed to remove cache file", "error", err)
+	}
+	fc.size -= fc.entries[name].Size
+	delete(fc.entries, name)
+	fc.modified = true
+}
+
+// saveManifest writes the manifest if it changed, logging and returning the error if it cannot.
+// The caller must hold fc.mu.
+func (fc *FileCache) saveManifest() error {
+	if !fc.modified {
+		return nil
+	}
+	data, err := json.Marshal(fc.entries)
+	if err != nil {
+		fc.log().Warn("failed to encode cache manifest", "error", err)
+		return fmt.Errorf("failed to encode cache manifest: %w", err)
+	}
+	if err := fc.writeFile(fileCacheManifest, data); err != nil {
+		fc.log().Warn("failed to store cache manifest", "error", err)
+		return fmt.Errorf("failed to store cache manifest: %w", err)
+	}
+	fc.modified = false
+	return nil
+}
+
+// writeFile atomically replaces the file name in the cache directory with data.
+func (fc *FileCache) writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(fc.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(fc.dir, name)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package jquants
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	for _, codec := range []Codec{GzipCodec{}, JSONCodec{}} {
+		t.Run(codec.Extension(), func(t *testing.T) {
+			cache, err := NewFileCacheWithCodec(t.TempDir(), codec)
+			if err != nil {
+				t.Fatalf("Failed to create file cache: %v", err)
+			}
+			key := BaseURL + "/equities/bars/daily?code=13010"
+			if _, ok := cache.Get(key); ok {
+				t.Error("Expected cache miss")
+			}
+			cache.Set(key, []byte(`{"data":[]}`), 0)
+			body, ok := cache.Get(key)
+			if !ok || !bytes.Equal(body, []byte(`{"data":[]}`)) {
+				t.Errorf("Unexpected cached body: %q, %v", body, ok)
+			}
+		})
+	}
+}
+
+func TestFileCache_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCacheWithCodec(dir, JSONCodec{})
+	if err != nil {
+		t.Fatalf("Failed to create file cache: %v", err)
+	}
+	cache.Set("historical", []byte(`{"data":[1]}`), 0)
+	cache.Set("expired", []byte(`{"data":[2]}`), time.Nanosecond)
+	cache.Set("recent", []byte(`{"data":[3]}`), time.Hour)
+	time.Sleep(time.Millisecond)
+
+	// A second run reads the TTLs back from the manifest.
+	cache, err = NewFileCacheWithCodec(dir, JSONCodec{})
+	if err != nil {
+		t.Fatalf("Failed to reopen file cache: %v", err)
+	}
+	if _, ok := cache.Get("historical"); !ok {
+		t.Error("Expected entry without TTL to persist")
+	}
+	if _, ok := cache.Get("recent"); !ok {
+		t.Error("Expected unexpired entry to persist")
+	}
+	if _, ok := cache.Get("expired"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+	if cache.Size() != int64(2*len(`{"data":[1]}`)) {
+		t.Errorf("Unexpected cache size: %d", cache.Size())
+	}
+}
+
+func TestFileCache_MaxSize(t *testing.T) {
+	cache, err := NewFileCacheWithCodec(t.TempDir(), JSONCodec{})
+	if err != nil {
+		t.Fatalf("Failed to create file cache: %v", err)
+	}
+	body := []byte(`{"data":[]}`)
+	cache.Set("a", body, 0)
+	time.Sleep(time.Millisecond)
+	cache.Set("b", body, 0)
+	time.Sleep(time.Millisecond)
+	cache.Get("a")
+	cache.SetMaxSize(int64(2 * len(body)))
+	cache.Set("c", body, 0)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected entry %s to be kept", key)
+		}
+	}
+	if cache.Size() != int64(2*len(body)) {
+		t.Errorf("Unexpected cache size: %d", cache.Size())
+	}
+}
+
+func TestFileCache_Flush(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCacheWithCodec(dir, JSONCodec{})
+	if err != nil {
+		t.Fatalf("Failed to create file cache: %v", err)
+	}
+	body := []byte(`{"data":[]}`)
+	cache.Set("a", body, 0)
+	time.Sleep(time.Millisecond)
+	cache.Set("b", body, 0)
+	time.Sleep(time.Millisecond)
+	cache.Get("a")
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Failed to flush file cache: %v", err)
+	}
+
+	// The next run evicts by the flushed last-use times, so a outlives b.
+	cache, err = NewFileCacheWithCodec(dir, JSONCodec{})
+	if err != nil {
+		t.Fatalf("Failed to reopen file cache: %v", err)
+	}
+	cache.SetMaxSize(int64(len(body)))
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected entry b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected recently read entry a to be kept")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte("a"), 0)
+	cache.Set("b", []byte("b"), 0)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected cache hit for a")
+	}
+	cache.Set("c", []byte("c"), 0)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	if body, ok := cache.Get("a"); !ok || string(body) != "a" {
+		t.Errorf("Unexpected entry a: %q, %v", body, ok)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Unexpected number of entries: %d", cache.Len())
+	}
+
+	cache.Set("d", []byte("d"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := cache.Get("d"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client := NewClient(BaseURL, "test", WithCacheTTL(0, time.Minute))
+	now := time.Now().In(jst)
+	today := now.Format(dateLayout)
+	lastWeek := now.AddDate(0, 0, -7).Format(dateLayout)
+	page := []byte(`{"data":[{"Date":"2023-01-04"}]}`)
+	tests := []struct {
+		path  string
+		query url.Values
+		body  []byte
+		want  time.Duration
+	}{
+		{"/equities/bars/daily", url.Values{"code": {"72030"}, "from": {"2023-01-01"}, "to": {"2023-12-31"}}, page, 0},
+		{"/equities/bars/daily", url.Values{"date": {"20230104"}}, page, 0},
+		{"/equities/bars/daily", url.Values{"code": {"72030"}, "from": {"2023-01-01"}, "to": {today}}, page, time.Minute},
+		{"/equities/bars/daily", url.Values{"date": {today}}, page, time.Minute},
+		{"/equities/bars/daily", url.Values{"code": {"72030"}}, page, time.Minute},
+		{"/equities/bars/daily", url.Values{"date": {lastWeek}}, page, 0},
+		{"/equities/bars/daily", url.Values{"date": {"2023-01-04"}}, []byte(`{"data":[]}`), time.Minute},
+		{"/equities/bars/daily", url.Values{"date": {"2023-01-04"}}, []byte(`{"pagination_key":"x","data":[ ]}`), time.Minute},
+		{"/markets/margin-interest", url.Values{"date": {lastWeek}}, page, time.Minute},
+		{"/markets/margin-interest", url.Values{"date": {"2023-01-06"}}, page, 0},
+		{"/markets/short-selling-positions", url.Values{"calculated_date": {lastWeek}}, page, time.Minute},
+		{"/fins/statements", url.Values{"date": {lastWeek}}, page, 0},
+	}
+	for _, tt := range tests {
+		if got := client.cacheTTL(tt.path, tt.query, tt.body); got != tt.want {
+			t.Errorf("cacheTTL(%s, %v, %s) = %v, want %v", tt.path, tt.query, tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package jquants
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Calendar is an in-memory trading calendar that answers date queries without calling the API.
+// Build it from fetched entries with [NewCalendar] or load a dump written by [WriteCalendar]
+// with [LoadCalendarFromFile], e.g. for reproducible backtests without network access.
+type Calendar struct {
+	// days holds the entries sorted by date, one per date.
+	days []TradingCalendar
+}
+
+// NewCalendar creates a Calendar from TradingCalendar entries, e.g. the result of [Client.TradingCalendar].
+// Entries may be unordered; if a date appears more than once, the first entry is kept.
+func NewCalendar(entries []TradingCalendar) *Calendar {
+	days := slices.Clone(entries)
+	slices.SortStableFunc(days, func(a, b TradingCalendar) int { return strings.Compare(a.Date, b.Date) })
+	days = slices.CompactFunc(days, func(a, b TradingCalendar) bool { return a.Date == b.Date })
+	return &Calendar{days: days}
+}
+
+// LoadCalendarFromFile reads a calendar dump written by [WriteCalendar].
+// The file has the same shape as the /markets/calendar API response.
+func LoadCalendarFromFile(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar file: %w", err)
+	}
+	defer f.Close()
+	var r tradingCalendarResponse
+	if err := json.NewDecoder(f).Decode(&r); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar file: %w", err)
+	}
+	return NewCalendar(r.Data), nil
+}
+
+// WriteCalendar writes entries as JSON in the shape of the /markets/calendar API response,
+// so the output can be read back with [LoadCalendarFromFile].
+func WriteCalendar(w io.Writer, entries []TradingCalendar) error {
+	type day struct {
+		Date            string `json:"Date"`
+		HolidayDivision string `json:"HolDiv"`
+	}
+	data := make([]day, 0, len(entries))
+	for _, e := range entries {
+		data = append(data, day{Date: e.Date, HolidayDivision: strconv.Itoa(int(e.DayType))})
+	}
+	if err := json.NewEncoder(w).Encode(struct {
+		Data []day `json:"data"`
+	}{data}); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// Days returns the calendar entries sorted by date.
+func (c *Calendar) Days() []TradingCalendar {
+	return slices.Clone(c.days)
+}
+
+// Day returns the entry for date (YYYY-MM-DD) and whether the calendar covers it.
+func (c *Calendar) Day(date string) (TradingCalendar, bool) {
+	i, ok := c.search(date)
+	if !ok {
+		return TradingCalendar{}, false
+	}
+	return c.days[i], true
+}
+
+// IsTradingDay reports whether date (YYYY-MM-DD) is a trading day. Dates outside the calendar are not trading days.
+func (c *Calendar) IsTradingDay(date string) bool {
+	day, ok := c.Day(date)
+	return ok && day.IsTradingDay()
+}
+
+// NextTradingDay returns the first trading day strictly after date, or false if the calendar has none.
+func (c *Calendar) NextTradingDay(date string) (string, bool) {
+	i, ok := c.search(date)
+	if ok {
+		i++
+	}
+	for ; i < len(c.days); i++ {
+		if c.days[i].IsTradingDay() {
+			return c.days[i].Date, true
+		}
+	}
+	return "", false
+}
+
+// PreviousTradingDay returns the last trading day strictly before date, or false if the calendar has none.
+func (c *Calendar) PreviousTradingDay(date string) (string, bool) {
+	i, _ := c.search(date)
+	for i--; i >= 0; i-- {
+		if c.days[i].IsTradingDay() {
+			return c.days[i].Date, true
+		}
+	}
+	return "", false
+}
+
+// TradingDays returns the trading days between from and to (inclusive, YYYY-MM-DD) in ascending order.
+func (c *Calendar) TradingDays(from, to string) []string {
+	days := make([]string, 0)
+	for i, _ := c.search(from); i < len(c.days) && c.days[i].Date <= to; i++ {
+		if c.days[i].IsTradingDay() {
+			days = append(days, c.days[i].Date)
+		}
+	}
+	return days
+}
+
+// MissingTradingDays is the offline variant of [MissingTradingDays]: it returns the trading days in the
+// calendar for which prices has no record.
+func (c *Calendar) MissingTradingDays(prices []StockPrice) []string {
+	return missingTradingDays(c.days, prices)
+}
+
+func (c *Calendar) search(date string) (int, bool) {
+	return slices.BinarySearchFunc(c.days, date, func(d TradingCalendar, date string) int {
+		return strings.Compare(d.Date, date)
+	})
+}
@@ -0,0 +1,49 @@
+package jquants
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadCalendarFromFile(t *testing.T) {
+	entries := []TradingCalendar{
+		{Date: "2024-01-05", DayType: 1},
+		{Date: "2024-01-04", DayType: 1},
+		{Date: "2024-01-06", DayType: 0},
+		{Date: "2024-01-07", DayType: 0},
+		{Date: "2024-01-08", DayType: 0},
+		{Date: "2024-01-09", DayType: 1},
+	}
+	path := filepath.Join(t.TempDir(), "calendar.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCalendar(f, entries); err != nil {
+		t.Fatalf("Failed to write calendar: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	calendar, err := LoadCalendarFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load calendar: %v", err)
+	}
+	if len(calendar.Days()) != len(entries) {
+		t.Errorf("Unexpected number of days: %d", len(calendar.Days()))
+	}
+	if !calendar.IsTradingDay("2024-01-05") || calendar.IsTradingDay("2024-01-08") || calendar.IsTradingDay("2024-02-01") {
+		t.Error("Unexpected IsTradingDay result")
+	}
+	if next, ok := calendar.NextTradingDay("2024-01-05"); !ok || next != "2024-01-09" {
+		t.Errorf("Unexpected next trading day: %s", next)
+	}
+	if prev, ok := calendar.PreviousTradingDay("2024-01-07"); !ok || prev != "2024-01-05" {
+		t.Errorf("Unexpected previous trading day: %s", prev)
+	}
+	if days := calendar.TradingDays("2024-01-05", "2024-01-09"); !slices.Equal(days, []string{"2024-01-05", "2024-01-09"}) {
+		t.Errorf("Unexpected trading days: %v", days)
+	}
+}
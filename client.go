@@ -8,23 +8,39 @@
 //
 //	client := jquants.NewClient(jquants.BaseURL, "your_api_key")
 //
-//	issues, err := client.IssueInformation(ctx, jquants.IssueInformationRequest{})
+//	issues, err := client.IssueInformation(ctx, jquants.IssueInformationRequest{AllData: true})
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 package jquants
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 const Version = "0.6.0"
@@ -32,6 +48,19 @@ const Version = "0.6.0"
 // BaseURL is the default base URL for the J-Quants API v2.
 const BaseURL = "https://api.jquants.com/v2"
 
+// AuthBaseURL is the default base URL of the token endpoints (/token/auth_user and /token/auth_refresh) used by
+// mail/password authentication, which are served by the J-Quants API v1.
+const AuthBaseURL = "https://api.jquants.com/v1"
+
+// dateLayout is the YYYY-MM-DD date format used by the J-Quants API.
+const dateLayout = "2006-01-02"
+
+// jst is Japan Standard Time, the time zone of all J-Quants dates. Japan does not observe daylight saving time.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// timeNow is the clock used to resolve open date bounds to today's date. Tests replace it.
+var timeNow = time.Now
+
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -45,9 +74,16 @@ type Client struct {
 	// baseURL is the base URL for API requests. Defaults to BaseURL constant.
 	baseURL string
 
+	// authBaseURL, if set, is the base URL of the token endpoints (see [WithAuthBaseURL]).
+	authBaseURL string
+
 	// apiKey is the J-Quants API key for authentication.
 	apiKey string
 
+	// auth holds the credentials and tokens set by NewClientWithCredentials or Authenticate.
+	// When set, requests send an ID token instead of apiKey.
+	auth tokenSource
+
 	userAgent string
 
 	// retryInterval is the duration to wait before retrying after a 500 error.
@@ -55,9 +91,60 @@ type Client struct {
 	retryInterval time.Duration
 
 	// loopTimeout is the maximum duration for paginated requests.
-	// If fetching all pages takes longer than this, the request will be cancelled.
+	// If fetching all pages takes longer than this, the request will be cancelled
+	// and the error reports how many pages were fetched before the timeout.
 	// Defaults to 20 seconds.
 	loopTimeout time.Duration
+
+	// forbiddenRetries is the number of times a page request is retried after a 403 that does not
+	// look like a plan restriction (e.g., while new credentials propagate). Defaults to 1.
+	forbiddenRetries int
+
+	// skipMalformedRecords makes paginated methods skip records that fail to decode instead of aborting.
+	// Defaults to false.
+	skipMalformedRecords bool
+
+	// stableOrder makes paginated methods sort their results by date and then code. Defaults to false.
+	stableOrder bool
+
+	// cache stores raw responses keyed by request URL. Nil disables caching.
+	cache Cache
+
+	// historicalCacheTTL and recentCacheTTL are the cache TTLs of responses that end before and on or after
+	// today (see [WithCacheTTL]).
+	historicalCacheTTL time.Duration
+	recentCacheTTL     time.Duration
+
+	// rateLimiter, if set, is waited on before every HTTP request and is shared by all goroutines using the client.
+	rateLimiter *rate.Limiter
+
+	// maxConcurrency bounds the number of concurrent fetches in batch helpers.
+	// Zero means it is derived from the rate limiter (see [WithMaxConcurrency]).
+	maxConcurrency int
+
+	// endpointTimeouts maps URL path prefixes to per-request timeouts (see [WithEndpointTimeouts]).
+	endpointTimeouts map[string]time.Duration
+
+	// inFlightLimit, if set, bounds the response bytes read by running batch fetches (see [WithMaxInFlightBytes]).
+	inFlightLimit *inFlightLimit
+
+	// middlewares wrap the HTTP client's transport, outermost first (see [WithMiddleware]).
+	middlewares []func(http.RoundTripper) http.RoundTripper
+
+	// logger receives the client's internal log messages (retries, body close failures).
+	// Defaults to slog.Default().
+	logger *slog.Logger
+
+	// warningHandler, if set, receives non-fatal issues encountered while fetching.
+	warningHandler func(Warning)
+
+	// startJitter is the upper bound of the random delay between goroutine launches in batch helpers.
+	// Defaults to 20 milliseconds.
+	startJitter time.Duration
+
+	// availableIndices caches the result of AvailableIndices, guarded by indicesMu.
+	availableIndices []string
+	indicesMu        sync.Mutex
 }
 
 type Option func(*Client)
@@ -68,6 +155,30 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithBaseURL overrides the API base URL, e.g. to point a [NewClientFromEnv] or [NewClientWithCredentials]
+// client at a mock server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithAuthBaseURL sets the base URL of the token endpoints used by mail/password authentication.
+// By default it is [AuthBaseURL] for a client of [BaseURL], and the API base URL itself for any other base URL,
+// so a mock server passed to [WithBaseURL] or [NewTestClient] also serves the token endpoints.
+func WithAuthBaseURL(authBaseURL string) Option {
+	return func(c *Client) {
+		c.authBaseURL = authBaseURL
+	}
+}
+
+// WithAPIKey overrides the API key passed to the constructor.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
 func WithRetryInterval(retryInterval time.Duration) Option {
 	return func(c *Client) {
 		c.retryInterval = retryInterval
@@ -80,10 +191,152 @@ func WithLoopTimeout(loopTimeout time.Duration) Option {
 	}
 }
 
+// WithForbiddenRetries sets how many times a 403 response is retried when its message does not indicate a
+// plan restriction. Plan-restricted requests always fail immediately. Set to 0 to disable.
+func WithForbiddenRetries(retries int) Option {
+	return func(c *Client) {
+		c.forbiddenRetries = retries
+	}
+}
+
+// WithSkipMalformedRecords makes paginated methods skip records that fail to decode and keep paginating.
+// The skipped records are reported through a [MalformedRecordsError] returned alongside the decoded data.
+func WithSkipMalformedRecords(skip bool) Option {
+	return func(c *Client) {
+		c.skipMalformedRecords = skip
+	}
+}
+
+// WithStableOrder makes slice-returning methods sort their results by date and then code (or sector or
+// market section, depending on the record type) before returning, so the output does not depend on page
+// arrival order, chunking, or resume boundaries. Types that can have several records per date and code are
+// further ordered by a distinguishing field: the holder for short-selling positions, the disclosure number
+// for financial statements, the reference number for dividends, the emergency margin division for futures
+// and options, and the published date for investor types. The WithChannel variants are unaffected.
+func WithStableOrder(stable bool) Option {
+	return func(c *Client) {
+		c.stableOrder = stable
+	}
+}
+
+// WithCache enables a read-through response cache. See [Cache].
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// defaultRecentCacheTTL is the cache TTL of responses whose range reaches today.
+const defaultRecentCacheTTL = 15 * time.Minute
+
+// WithCacheTTL sets how long cached responses stay valid. historical applies to requests whose date or
+// "to" bound is before today in JST, whose data does not change; recent applies to every other request,
+// including those without a date, and to pages without records. A TTL of zero or less means the response
+// never expires. The defaults are 0 (never) for historical and 15 minutes for recent responses.
+//
+// Endpoints published with a delay (weekly margin balances and investor types, short selling positions,
+// statements, dividends, and breakdowns) count a date as historical only once a conservative publication lag
+// of 3 to 14 days has passed, so late-published records are not hidden by a never-expiring entry.
+//
+// Split-adjusted prices of past days change when a later split is applied. Set a finite historical TTL
+// if cached adjusted prices must follow such revisions.
+func WithCacheTTL(historical, recent time.Duration) Option {
+	return func(c *Client) {
+		c.historicalCacheTTL = historical
+		c.recentCacheTTL = recent
+	}
+}
+
+// WithRateLimiter makes the client wait on limiter before every HTTP request.
+// Cached responses do not consume the limiter.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of concurrent fetches used by every batch helper.
+// By default it is twice the rate limiter's requests per second (at least 1),
+// or defaultMaxConcurrency without a rate limiter. The rate limiter still gates the actual request rate,
+// so a higher concurrency never exceeds the configured rate.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(c *Client) {
+		c.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithEndpointTimeouts sets per-request timeouts by URL path prefix, e.g. a tight timeout for "/equities/master"
+// and a generous one for "/equities/bars/daily". The longest matching prefix wins; requests to paths without a
+// match only use the HTTP client's timeout. Each timeout covers one request including reading its body.
+func WithEndpointTimeouts(timeouts map[string]time.Duration) Option {
+	return func(c *Client) {
+		c.endpointTimeouts = maps.Clone(timeouts)
+	}
+}
+
+// WithMaxInFlightBytes bounds the total size of the decoded response bodies read by the fetches that batch helpers
+// are currently running. A new fetch waits while the limit is used up, and a fetch's bytes are released once it
+// returns. At least one fetch always proceeds, so a single response larger than the limit cannot deadlock.
+// A maxBytes of zero or less means no limit.
+//
+// This is an in-flight limit only, not a ceiling on the batch's memory: the results of finished fetches are
+// still held in the returned map until the batch completes, and they are not counted. To bound the total,
+// split the batch into smaller calls or stream the records with the WithChannel or Seq variants.
+func WithMaxInFlightBytes(maxBytes int64) Option {
+	return func(c *Client) {
+		if maxBytes <= 0 {
+			c.inFlightLimit = nil
+			return
+		}
+		c.inFlightLimit = newInFlightLimit(maxBytes)
+	}
+}
+
+// WithWarningHandler sets a callback that receives non-fatal issues (skipped records, empty pages, retries)
+// encountered while fetching. The handler may be called concurrently by batch helpers.
+func WithWarningHandler(handler func(Warning)) Option {
+	return func(c *Client) {
+		c.warningHandler = handler
+	}
+}
+
+func WithStartJitter(startJitter time.Duration) Option {
+	return func(c *Client) {
+		c.startJitter = startJitter
+	}
+}
+
+// WithMiddleware wraps the HTTP client's transport with middlewares, for example to add tracing spans,
+// request IDs, or metrics around each J-Quants call. Middlewares are applied in order: the first one is the
+// outermost and sees each request first and its response last. Repeated calls append to the chain.
+//
+// The chain wraps the transport of the [WithHTTPClient] client (http.DefaultTransport if it has none),
+// which is not modified. The rate limiter (see [WithRateLimiter]) and the endpoint timeouts are applied
+// before the request reaches the transport, so middlewares run after the limiter wait and each retry
+// passes through them as a separate request. Token requests of [NewClientWithCredentials] clients pass
+// through them too.
+func WithMiddleware(middlewares ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithLogger sets the logger used for the client's internal messages, such as retry notices and failures to
+// close response bodies, instead of slog.Default(). A nil logger silences them. A [FileCache] passed with
+// [WithCache] logs to the same logger unless it was given its own with [FileCache.SetLogger].
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
+
 // NewClient creates a new J-Quants API client.
 // baseURL is the API base URL (use [BaseURL] for the default).
-// apiKey is the J-Quants API key for authentication.
-// Optional [Option] functions can be used to customize the client (e.g., [WithHTTPClient], [WithRetryInterval], [WithLoopTimeout]).
+// apiKey is the J-Quants API key for authentication; if empty, J_QUANTS_API_KEY is read at request time.
+// Optional [Option] functions can be used to customize the client (e.g., [WithHTTPClient], [WithRetryInterval], [WithLoopTimeout], [WithRateLimiter], [WithMaxConcurrency], [WithStartJitter]).
 func NewClient(baseURL, apiKey string, opts ...Option) *Client {
 	client := &Client{
 		httpClient: http.DefaultClient,
@@ -96,19 +349,233 @@ func NewClient(baseURL, apiKey string, opts ...Option) *Client {
 			runtime.GOOS,
 			runtime.GOARCH,
 		),
-		retryInterval: 5 * time.Second,
-		loopTimeout:   20 * time.Second,
+		retryInterval:    5 * time.Second,
+		loopTimeout:      20 * time.Second,
+		forbiddenRetries: 1,
+		startJitter:      20 * time.Millisecond,
+		recentCacheTTL:   defaultRecentCacheTTL,
+		logger:           slog.Default(),
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	if l, ok := client.cache.(interface{ setDefaultLogger(*slog.Logger) }); ok {
+		l.setDefaultLogger(client.logger)
+	}
+	if len(client.middlewares) > 0 {
+		client.httpClient = wrapTransport(client.httpClient, client.middlewares)
+	}
 	return client
 }
 
+// wrapTransport returns an HTTP client whose transport is client's wrapped by middlewares, the first being
+// outermost. An *http.Client is copied; any other HTTPClient becomes the innermost transport.
+func wrapTransport(client HTTPClient, middlewares []func(http.RoundTripper) http.RoundTripper) *http.Client {
+	var wrapped http.Client
+	var transport http.RoundTripper
+	if hc, ok := client.(*http.Client); ok {
+		wrapped = *hc
+		transport = hc.Transport
+	} else {
+		transport = doerTransport{client}
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for _, mw := range slices.Backward(middlewares) {
+		transport = mw(transport)
+	}
+	wrapped.Transport = transport
+	return &wrapped
+}
+
+// doerTransport adapts an HTTPClient to http.RoundTripper.
+type doerTransport struct {
+	client HTTPClient
+}
+
+func (t doerTransport) RoundTrip(req *http.Request) (*http.Response, error) { return t.client.Do(req) }
+
+// defaultMaxConcurrency is the batch helper concurrency when neither WithMaxConcurrency nor a rate limiter is configured.
+const defaultMaxConcurrency = 4
+
+// concurrency returns the maximum number of concurrent fetches for batch helpers.
+func (c *Client) concurrency() int {
+	if c.maxConcurrency > 0 {
+		return c.maxConcurrency
+	}
+	if c.rateLimiter == nil || c.rateLimiter.Limit() == rate.Inf {
+		return defaultMaxConcurrency
+	}
+	return max(1, int(2*c.rateLimiter.Limit()))
+}
+
+type (
+	apiKeyContextKey  struct{}
+	idTokenContextKey struct{}
+)
+
+// ContextWithAPIKey returns a copy of ctx whose requests are authenticated with apiKey instead of the
+// client's default credential. This lets a single Client serve several J-Quants accounts.
+// The override applies only to requests made with the returned context. Cached responses are kept apart
+// per overriding credential, but all other client settings (the shared rate limiter, retries, and timeouts)
+// still apply across credentials unless configured separately.
+func ContextWithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+// ContextWithIDToken returns a copy of ctx whose requests are authenticated with the ID token idToken
+// (sent as "Authorization: Bearer <idToken>") instead of the client's default credential, like
+// [ContextWithAPIKey]. It takes precedence over an API key set with ContextWithAPIKey. The client does not
+// renew an overriding token: a request it authenticates that the API rejects with 401 returns [Unauthorized].
+func ContextWithIDToken(ctx context.Context, idToken string) context.Context {
+	return context.WithValue(ctx, idTokenContextKey{}, idToken)
+}
+
+// contextCredential returns the header name and value of a credential set with ContextWithIDToken or
+// ContextWithAPIKey, and whether one is set.
+func contextCredential(ctx context.Context) (string, string, bool) {
+	if idToken, ok := ctx.Value(idTokenContextKey{}).(string); ok && idToken != "" {
+		return "Authorization", "Bearer " + idToken, true
+	}
+	if apiKey, ok := ctx.Value(apiKeyContextKey{}).(string); ok && apiKey != "" {
+		return "x-api-key", apiKey, true
+	}
+	return "", "", false
+}
+
+// cacheKey returns the response cache key of the request URL u. Requests with the client's own credential
+// use the URL itself; a credential set on ctx adds a hash of it, so tenants never read each other's entries.
+func cacheKey(ctx context.Context, u *url.URL) string {
+	header, value, ok := contextCredential(ctx)
+	if !ok {
+		return u.String()
+	}
+	sum := sha256.Sum256([]byte(header + ":" + value))
+	return u.String() + "#credential=" + hex.EncodeToString(sum[:8])
+}
+
+// APIKeyEnv is the environment variable read by [NewClientFromEnv] and by clients created without an API key.
+const APIKeyEnv = "J_QUANTS_API_KEY"
+
+// ErrNoAPIKey is returned (wrapped) when no API key is configured and the J_QUANTS_API_KEY environment
+// variable is unset or empty. Check for it with errors.Is to prompt the user to set the variable.
+var ErrNoAPIKey = errors.New("no API key configured")
+
+// getAPIKey reads the API key from the J_QUANTS_API_KEY environment variable.
+func getAPIKey() (string, error) {
+	apiKey := os.Getenv(APIKeyEnv)
+	if apiKey == "" {
+		return "", fmt.Errorf("%w: set the %s environment variable", ErrNoAPIKey, APIKeyEnv)
+	}
+	return apiKey, nil
+}
+
+// NewClientFromEnv creates a client for [BaseURL] authenticated with the J_QUANTS_API_KEY environment variable.
+// It returns an error wrapping [ErrNoAPIKey] if the variable is unset or empty.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	apiKey, err := getAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(BaseURL, apiKey, opts...), nil
+}
+
+// testRetryInterval is the retry interval of clients created by NewTestClient.
+const testRetryInterval = 10 * time.Millisecond
+
+// NewTestClient creates a client for tests that talk to a mock server such as an httptest.Server.
+// It sends requests to baseURL with apiKey through hc (http.DefaultClient if nil), so it needs neither
+// network access nor J_QUANTS_API_KEY, and it retries after 10ms instead of 5s and launches batch goroutines
+// without jitter so that tests of the retry and batch paths run quickly. opts are applied after these settings.
+func NewTestClient(baseURL, apiKey string, hc *http.Client, opts ...Option) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	defaults := []Option{WithHTTPClient(hc), WithRetryInterval(testRetryInterval), WithStartJitter(0)}
+	return NewClient(baseURL, apiKey, append(defaults, opts...)...)
+}
+
+// requestAPIKey returns the API key for a request, preferring an override set with ContextWithAPIKey.
+// A client created without an API key reads J_QUANTS_API_KEY at request time, so the variable may be set
+// after construction; if neither is available, the error wraps [ErrNoAPIKey].
+func (c *Client) requestAPIKey(ctx context.Context) (string, error) {
+	if apiKey, ok := ctx.Value(apiKeyContextKey{}).(string); ok && apiKey != "" {
+		return apiKey, nil
+	}
+	if c.apiKey != "" {
+		return c.apiKey, nil
+	}
+	return getAPIKey()
+}
+
+type loopTimeoutContextKey struct{}
+
+// withLoopTimeout returns a copy of ctx whose paginated fetches are bounded by timeout instead of the client's loopTimeout.
+// It is used by methods that are known to pull far more pages than a typical query.
+func withLoopTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, loopTimeoutContextKey{}, timeout)
+}
+
+// requestLoopTimeout returns the pagination loop timeout for ctx, preferring an override set with withLoopTimeout.
+func (c *Client) requestLoopTimeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(loopTimeoutContextKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return c.loopTimeout
+}
+
 type parameters interface {
 	values() (url.Values, error)
 }
 
+// ValidateRequest runs the local validation and query building of an endpoint request
+// (e.g., a [StockPriceRequest]) without sending it, so query configurations can be checked without using quota.
+// It returns an error if the request is invalid or is not a request type of this package.
+func ValidateRequest(req any) error {
+	var params parameters
+	switch r := req.(type) {
+	case IssueInformationRequest:
+		params = issueInformationParameters{IssueInformationRequest: r}
+	case StockPriceRequest:
+		params = stockPriceParameters{StockPriceRequest: r}
+	case MorningSessionStockPriceRequest:
+		params = morningSessionStockPriceParameters{MorningSessionStockPriceRequest: r}
+	case InvestorTypeRequest:
+		params = investorTypeParameters{InvestorTypeRequest: r}
+	case MarginTradingOutstandingRequest:
+		params = marginTradingOutstandingParameters{MarginTradingOutstandingRequest: r}
+	case ShortSellingValueRequest:
+		params = shortSellingValueParameters{ShortSellingValueRequest: r}
+	case TradingCalendarRequest:
+		params = tradingCalendarParameters{TradingCalendarRequest: r}
+	case IndexPriceRequest:
+		params = indexPriceParameters{IndexPriceRequest: r}
+	case TopixPriceRequest:
+		params = topixPriceParameters{TopixPriceRequest: r}
+	case IndexOptionPriceRequest:
+		params = indexOptionPriceParameters{IndexOptionPriceRequest: r}
+	case FuturesPriceRequest:
+		params = futuresPriceParameters{FuturesPriceRequest: r}
+	case FinancialStatementsRequest:
+		params = financialStatementsParameters{FinancialStatementsRequest: r}
+	case BreakdownRequest:
+		params = breakdownParameters{BreakdownRequest: r}
+	case ShortSellingPositionsRequest:
+		params = shortSellingPositionsParameters{ShortSellingPositionsRequest: r}
+	case DividendRequest:
+		params = dividendParameters{DividendRequest: r}
+	case EarningsAnnouncementRequest:
+		params = earningsAnnouncementParameters{EarningsAnnouncementRequest: r}
+	default:
+		return fmt.Errorf("unsupported request type %T", req)
+	}
+	if _, err := params.values(); err != nil {
+		return fmt.Errorf("failed to build query parameters: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) sendRequest(ctx context.Context, urlPath string, param parameters) (*http.Response, error) {
 	u, err := url.Parse(c.baseURL + urlPath)
 	if err != nil {
@@ -120,20 +587,192 @@ func (c *Client) sendRequest(ctx context.Context, urlPath string, param paramete
 	}
 	u.RawQuery = v.Encode()
 
+	cacheKey := cacheKey(ctx, u)
+	if c.cache != nil {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			chargeInFlight(ctx, len(body))
+			return cachedResponse(body), nil
+		}
+	}
+
+	authHeader, authValue, err := c.requestAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("failed to wait for rate limiter: %w", err)
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if timeout, ok := c.endpointTimeout(urlPath); ok {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
 	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to build request: %w", err)
 	}
 	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set(authHeader, authValue)
 	req.Header.Set("Accept-Encoding", "gzip")
 	resp, err := c.httpClient.Do(req)
+	if err != nil && isConnectionClosed(err) {
+		// All endpoints are idempotent GETs, so a request on a keep-alive connection the server closed is safe to resend.
+		c.logger.Warn("Retrying HTTP request", "error", err.Error())
+		resp, err = c.httpClient.Do(req)
+	}
+	if _, _, overridden := contextCredential(ctx); err == nil && resp.StatusCode == 401 && authHeader == "Authorization" && !overridden {
+		// The ID token may have been revoked before its expiry; renew it once and resend.
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
+		if authValue, err = c.renewIDToken(ctx, authValue); err != nil {
+			cancel()
+			return nil, err
+		}
+		req.Header.Set(authHeader, authValue)
+		resp, err = c.httpClient.Do(req)
+	}
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	if c.cache != nil && resp.StatusCode == 200 {
+		body, err := readBody(resp)
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		c.cache.Set(cacheKey, body, c.cacheTTL(urlPath, v, body))
+		chargeInFlight(ctx, len(body))
+		return cachedResponse(body), nil
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	if err := trackBody(ctx, resp); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 	return resp, nil
 }
 
+// isConnectionClosed reports whether err means the server closed the connection before responding.
+func isConnectionClosed(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// cancelOnClose releases a per-request timeout context once the response body has been consumed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// endpointTimeout returns the timeout configured with WithEndpointTimeouts for the longest prefix of urlPath.
+func (c *Client) endpointTimeout(urlPath string) (time.Duration, bool) {
+	var timeout time.Duration
+	longest := -1
+	for prefix, d := range c.endpointTimeouts {
+		if strings.HasPrefix(urlPath, prefix) && len(prefix) > longest {
+			timeout, longest = d, len(prefix)
+		}
+	}
+	return timeout, longest >= 0
+}
+
+// publicationLags are the calendar days after a date during which an endpoint may still add or revise its
+// records, because the data is published with a delay: weekly margin balances and investor types, short selling
+// positions reported days after they are calculated, and statements, dividends, and breakdowns processed after the
+// trading day. The values are conservative; data older than the lag is treated as settled.
+var publicationLags = map[string]int{
+	"/markets/margin-interest":         14,
+	"/equities/investor-types":         14,
+	"/markets/short-selling-positions": 7,
+	"/fins/statements":                 3,
+	"/fins/dividend":                   3,
+	"/markets/breakdown":               3,
+}
+
+// cacheTTL returns the cache TTL of a response body to a request for urlPath with query v: the historical TTL
+// if its date or "to" bound is before today in JST minus the endpoint's publication lag, and the recent TTL
+// otherwise. Pages without records get the recent TTL, since their data may not be published yet.
+func (c *Client) cacheTTL(urlPath string, v url.Values, body []byte) time.Duration {
+	var end string
+	for _, key := range []string{"to", "date", "calculated_date", "disclosed_date"} {
+		if end = v.Get(key); end != "" {
+			break
+		}
+	}
+	if end == "" || emptyPage(body) {
+		return c.recentCacheTTL
+	}
+	settled := time.Now().In(jst).AddDate(0, 0, -publicationLags[urlPath]).Format("20060102")
+	if strings.ReplaceAll(end, "-", "") < settled {
+		return c.historicalCacheTTL
+	}
+	return c.recentCacheTTL
+}
+
+// emptyPage reports whether body is a response page whose "data" array is empty.
+// Bodies that cannot be parsed are reported as not empty.
+func emptyPage(body []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return false
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		if key == "data" {
+			tok, err := dec.Token()
+			return err == nil && tok == json.Delim('[') && !dec.More()
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// cachedResponse builds a successful response serving a body from the cache.
+// The body is uncompressed JSON, so no Content-Encoding is set.
+func cachedResponse(body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+// responseReader returns a reader over the decoded response body, decompressing it if the response is gzip-encoded.
+// Closing the returned reader does not close resp.Body.
+func responseReader(resp *http.Response) (io.ReadCloser, error) {
+	if !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Content-Encoding")), "gzip") {
+		return io.NopCloser(resp.Body), nil
+	}
+	return gzip.NewReader(resp.Body)
+}
+
+// readBody reads the whole decoded response body.
+func readBody(resp *http.Response) ([]byte, error) {
+	r, err := responseReader(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
 // HTTPError is the base type for HTTP error responses.
 type HTTPError struct {
 	StatusCode int
@@ -149,6 +788,10 @@ func (e HTTPError) Unwrap() error {
 	return e.Err
 }
 
+func (e HTTPError) httpStatus() int {
+	return e.StatusCode
+}
+
 // BadRequest represents an HTTP 400 error response.
 type BadRequest struct{ HTTPError }
 
@@ -160,6 +803,32 @@ type Unauthorized struct{ HTTPError }
 // This typically indicates the API key does not have permission for the requested resource.
 type Forbidden struct{ HTTPError }
 
+// forbiddenRetryDelay is the delay before retrying a transient 403 response.
+const forbiddenRetryDelay = time.Second
+
+// IsPlanRestriction reports whether the 403 was caused by the subscription plan
+// (e.g., an endpoint or date range not covered by the plan) rather than a transient authentication issue.
+func (e Forbidden) IsPlanRestriction() bool {
+	return e.Err != nil && strings.Contains(strings.ToLower(e.Err.Error()), "subscription")
+}
+
+// coveredRangePattern matches the date range in a plan restriction message such as
+// "Your subscription covers the following dates: 2023-01-09 ~ 2025-01-09".
+var coveredRangePattern = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})\s*~\s*(\d{4}-\d{2}-\d{2})`)
+
+// CoveredRange returns the date range (YYYY-MM-DD) the subscription plan covers, parsed from the error
+// message of a plan restriction. ok is false if the message does not state a range.
+func (e Forbidden) CoveredRange() (from, to string, ok bool) {
+	if !e.IsPlanRestriction() {
+		return "", "", false
+	}
+	m := coveredRangePattern.FindStringSubmatch(e.Err.Error())
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
 // PayloadTooLarge represents an HTTP 413 error response.
 // This occurs when the request parameters would result in too much data.
 type PayloadTooLarge struct{ HTTPError }
@@ -168,29 +837,239 @@ type PayloadTooLarge struct{ HTTPError }
 // The client automatically retries requests that receive this error.
 type InternalServerError struct{ HTTPError }
 
-func decodeResponse(resp *http.Response, body any) error {
-	gzipReader, err := gzip.NewReader(resp.Body)
+// ServiceUnavailable represents an HTTP 503 error response, typically returned during scheduled maintenance.
+// Paginated methods retry it a bounded number of times with exponential backoff,
+// waiting until Until instead when the end of the maintenance window is known.
+type ServiceUnavailable struct {
+	HTTPError
+	// Until is the announced end of the maintenance window, parsed from the Retry-After header or the
+	// error message (nil if unknown).
+	Until *time.Time
+}
+
+// TooManyRequests represents an HTTP 429 error response returned when the rate limit is exceeded.
+// Paginated methods retry it like [ServiceUnavailable], honoring the Retry-After header.
+type TooManyRequests struct {
+	HTTPError
+	// Until is the time after which requests may resume, parsed from the Retry-After header (nil if unknown).
+	Until *time.Time
+}
+
+// serviceUnavailableRetries is the number of times a 503 or 429 response is retried before giving up.
+const serviceUnavailableRetries = 3
+
+// retryAfter returns the Until time of a 503 or 429 error and whether err is one of them.
+func retryAfter(err error) (*time.Time, bool) {
+	var unavailable ServiceUnavailable
+	if errors.As(err, &unavailable) {
+		return unavailable.Until, true
+	}
+	var tooMany TooManyRequests
+	if errors.As(err, &tooMany) {
+		return tooMany.Until, true
+	}
+	return nil, false
+}
+
+// retryDelay returns how long to wait before retrying: until the given time if it is known and in the future,
+// otherwise backoff.
+func retryDelay(until *time.Time, backoff time.Duration) time.Duration {
+	if until != nil {
+		if d := time.Until(*until); d > 0 {
+			return d
+		}
+	}
+	return backoff
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying, using the same classification as
+// the client's own retries: HTTP 500, 503 (maintenance), 429 (rate limit), a 403 that is not a plan
+// restriction, and network errors such as a closed connection or a timeout. Context cancellation, an
+// expired context deadline (including [LoopTimeoutError]) and other HTTP errors are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.As(err, &LoopTimeoutError{}) {
+		return false
+	}
+	if errors.As(err, &InternalServerError{}) {
+		return true
+	}
+	if _, ok := retryAfter(err); ok {
+		return true
+	}
+	var forbidden Forbidden
+	if errors.As(err, &forbidden) {
+		return !forbidden.IsPlanRestriction()
+	}
+	var httpErr interface{ httpStatus() int }
+	if errors.As(err, &httpErr) {
+		return false
+	}
+	if isConnectionClosed(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// maintenanceEndPattern matches a date and time such as "2024-01-06 18:00" or "2024/01/06 18:00" (JST).
+var maintenanceEndPattern = regexp.MustCompile(`(\d{4})[-/](\d{2})[-/](\d{2})[ T](\d{2}):(\d{2})`)
+
+// maintenanceEnd extracts the end of a maintenance window from the Retry-After header or the error message.
+func maintenanceEnd(resp *http.Response, err error) *time.Time {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, convErr := strconv.Atoi(retryAfter); convErr == nil {
+			t := time.Now().Add(time.Duration(seconds) * time.Second)
+			return &t
+		}
+		if t, parseErr := http.ParseTime(retryAfter); parseErr == nil {
+			return &t
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	m := maintenanceEndPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil
+	}
+	t, parseErr := time.ParseInLocation("2006-01-02 15:04", fmt.Sprintf("%s-%s-%s %s:%s", m[1], m[2], m[3], m[4], m[5]), jst)
+	if parseErr != nil {
+		return nil
+	}
+	return &t
+}
+
+// sleepContext waits for d and reports whether it elapsed before ctx was done.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// LoopTimeoutError is returned when a paginated request exceeds the client's loop timeout
+// (see [WithLoopTimeout]) while the caller's own context is still active.
+// It unwraps to the underlying error, so errors.Is(err, context.DeadlineExceeded) still holds.
+type LoopTimeoutError struct {
+	// Timeout is the loop timeout that was exceeded.
+	Timeout time.Duration
+	// PagesFetched is the number of pages fetched successfully before the timeout.
+	PagesFetched int
+	// LastPaginationKey is the pagination key of the page that was being fetched (nil for the first page).
+	LastPaginationKey *string
+	Err               error
+}
+
+func (e LoopTimeoutError) Error() string {
+	return fmt.Sprintf(
+		"pagination loop timed out after %s with %d pages fetched, consider raising it with WithLoopTimeout: %v",
+		e.Timeout, e.PagesFetched, e.Err,
+	)
+}
+
+func (e LoopTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (c *Client) decodeResponse(resp *http.Response, body any) error {
+	bodyReader, err := responseReader(resp)
 	if err != nil {
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
 		return err
 	}
 	defer func() {
-		if clsErr := gzipReader.Close(); clsErr != nil {
-			slog.Warn("failed to close response body", "error", clsErr)
+		if clsErr := bodyReader.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
 		}
 	}()
-	if err := json.NewDecoder(gzipReader).Decode(body); err != nil {
+	if err := json.NewDecoder(bodyReader).Decode(body); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 	return nil
 }
 
+// RecordError describes a single record that failed to decode.
+type RecordError struct {
+	// Index is the position of the record within its page.
+	Index int
+	// Raw is the undecoded JSON of the record.
+	Raw json.RawMessage
+	Err error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// MalformedRecordsError is returned together with the successfully decoded data when
+// [WithSkipMalformedRecords] is enabled and some records were skipped.
+type MalformedRecordsError struct {
+	Records []RecordError
+}
+
+func (e MalformedRecordsError) Error() string {
+	return fmt.Sprintf("skipped %d malformed records: %v", len(e.Records), errors.Join(e.Unwrap()...))
+}
+
+func (e MalformedRecordsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Records))
+	for _, r := range e.Records {
+		errs = append(errs, r)
+	}
+	return errs
+}
+
+// records decodes a JSON array of T one element at a time.
+// If skipMalformed is set, elements that fail to decode are collected in errs instead of failing the whole page.
+type records[T any] struct {
+	items         []T
+	errs          []RecordError
+	skipMalformed bool
+}
+
+func (r *records[T]) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	r.items = make([]T, 0, len(raw))
+	for i, m := range raw {
+		var item T
+		if err := json.Unmarshal(m, &item); err != nil {
+			if !r.skipMalformed {
+				return err
+			}
+			r.errs = append(r.errs, RecordError{Index: i, Raw: m, Err: err})
+			continue
+		}
+		r.items = append(r.items, item)
+	}
+	return nil
+}
+
 // ErrResponse represents the error response body from the J-Quants API.
 type ErrResponse struct {
 	Message string `json:"message"`
 }
 
-func handleErrorResponse(resp *http.Response) error {
-	err := decodeErrorResponse(resp)
+func (c *Client) handleErrorResponse(resp *http.Response) error {
+	err := c.decodeErrorResponse(resp)
 	switch resp.StatusCode {
 	case 400:
 		return BadRequest{HTTPError{400, "bad request", err}}
@@ -200,78 +1079,561 @@ func handleErrorResponse(resp *http.Response) error {
 		return Forbidden{HTTPError{403, "forbidden", err}}
 	case 413:
 		return PayloadTooLarge{HTTPError{413, "payload too large", err}}
+	case 429:
+		return TooManyRequests{HTTPError: HTTPError{429, "too many requests", err}, Until: maintenanceEnd(resp, nil)}
 	case 500:
 		return InternalServerError{HTTPError{500, "internal server error", err}}
+	case 503:
+		return ServiceUnavailable{HTTPError: HTTPError{503, "service unavailable", err}, Until: maintenanceEnd(resp, err)}
 	default:
 		return err
 	}
 }
 
-func decodeErrorResponse(resp *http.Response) error {
+func (c *Client) decodeErrorResponse(resp *http.Response) error {
 	var errResp ErrResponse
-	if err := decodeResponse(resp, &errResp); err != nil {
+	if err := c.decodeResponse(resp, &errResp); err != nil {
 		return fmt.Errorf("failed to decode error response: %w", err)
 	}
 	return errors.New(errResp.Message)
 }
 
-// fetchAllPages fetches all pages of a paginated API endpoint.
-func fetchAllPages[T any, R Response[T]](
+// paginate calls fetchPage until the API stops returning a pagination key, passing each page to onPage.
+// The whole loop is bounded by the client's loopTimeout, or by an override set with withLoopTimeout.
+func paginate[T any, R Response[T]](
 	ctx context.Context,
 	c *Client,
 	fetchPage func(ctx context.Context, paginationKey *string) (R, error),
-) ([]T, error) {
-	data := make([]T, 0)
-	var paginationKey *string
-	ctx, cancel := context.WithTimeout(ctx, c.loopTimeout)
+	onPage func(resp R),
+) error {
+	parent := ctx
+	loopTimeout := c.requestLoopTimeout(ctx)
+	ctx, cancel := context.WithTimeout(ctx, loopTimeout)
 	defer cancel()
+	var paginationKey *string
+	var malformed []RecordError
+	pages, forbiddenAttempts, unavailableAttempts := 0, 0, 0
 	for {
 		resp, err := fetchPage(ctx, paginationKey)
 		if err != nil {
 			if errors.As(err, &InternalServerError{}) {
-				slog.Warn("Retrying HTTP request", "error", err.Error())
+				c.logger.Warn("Retrying HTTP request", "error", err.Error())
+				c.warn(WarningRetry, err.Error(), pages+1, paginationKey)
 				time.Sleep(c.retryInterval)
 				continue
 			}
-			return nil, err
+			var forbidden Forbidden
+			if errors.As(err, &forbidden) && !forbidden.IsPlanRestriction() && forbiddenAttempts < c.forbiddenRetries {
+				forbiddenAttempts++
+				c.logger.Warn("Retrying HTTP request", "error", err.Error())
+				c.warn(WarningRetry, err.Error(), pages+1, paginationKey)
+				if sleepContext(ctx, forbiddenRetryDelay) {
+					continue
+				}
+			}
+			if until, ok := retryAfter(err); ok && unavailableAttempts < serviceUnavailableRetries {
+				unavailableAttempts++
+				c.logger.Warn("Retrying HTTP request", "error", err.Error())
+				c.warn(WarningRetry, err.Error(), pages+1, paginationKey)
+				if sleepContext(ctx, retryDelay(until, c.retryInterval<<(unavailableAttempts-1))) {
+					continue
+				}
+			}
+			if parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return LoopTimeoutError{
+					Timeout:           loopTimeout,
+					PagesFetched:      pages,
+					LastPaginationKey: paginationKey,
+					Err:               err,
+				}
+			}
+			return err
 		}
-		data = append(data, resp.Items()...)
-		paginationKey = resp.NextPageKey()
+		pages++
+		forbiddenAttempts, unavailableAttempts = 0, 0
+		if m, ok := any(resp).(interface{ malformedRecords() []RecordError }); ok {
+			if errs := m.malformedRecords(); len(errs) > 0 {
+				c.warn(WarningMalformedRecords, fmt.Sprintf("skipped %d malformed records", len(errs)), pages, paginationKey)
+				malformed = append(malformed, errs...)
+			}
+		}
+		onPage(resp)
+		next := resp.NextPageKey()
+		if pageLen[T](resp) == 0 && next != nil {
+			c.warn(WarningEmptyPage, "page contained no records", pages, paginationKey)
+		}
+		paginationKey = next
 		if paginationKey == nil {
-			break
+			if len(malformed) > 0 {
+				return MalformedRecordsError{Records: malformed}
+			}
+			return nil
 		}
 	}
-	return data, nil
 }
 
-// fetchAllPagesWithChannel fetches all pages and sends each item to a channel.
-func fetchAllPagesWithChannel[T any, R Response[T]](
+// pageLen returns the number of records decoded on a page, including streamed ones.
+func pageLen[T any](resp Response[T]) int {
+	if s, ok := resp.(interface{ recordCount() int }); ok {
+		return s.recordCount()
+	}
+	return len(resp.Items())
+}
+
+// entitlementProbeAge is how far back the probe date used by Entitlements lies.
+// It is old enough to be outside the free plan's delay window and recent enough to be inside its history window.
+const entitlementProbeAge = 120 * 24 * time.Hour
+
+// Entitlements reports which data endpoints the API key's plan can access.
+// It sends one small request to each endpoint and returns a map keyed by endpoint path
+// (e.g., "/equities/bars/daily"): true if the request succeeded, false if the API returned 403 Forbidden.
+// Any other error aborts the probe and is returned.
+func (c *Client) Entitlements(ctx context.Context) (map[string]bool, error) {
+	date := time.Now().In(jst).Add(-entitlementProbeAge).Format(dateLayout)
+	code, indexCode := "13010", "0000"
+	probes := []struct {
+		path   string
+		params parameters
+	}{
+		{"/equities/master", issueInformationParameters{IssueInformationRequest: IssueInformationRequest{Code: &code}}},
+		{"/equities/bars/daily", stockPriceParameters{StockPriceRequest: StockPriceRequest{Code: &code, From: &date, To: &date}}},
+		{"/equities/investor-types", investorTypeParameters{InvestorTypeRequest: InvestorTypeRequest{From: &date, To: &date}}},
+		{"/markets/margin-interest", marginTradingOutstandingParameters{MarginTradingOutstandingRequest: MarginTradingOutstandingRequest{Code: &code, From: &date, To: &date}}},
+		{"/markets/short-ratio", shortSellingValueParameters{ShortSellingValueRequest: ShortSellingValueRequest{Date: &date}}},
+		{"/markets/calendar", tradingCalendarParameters{TradingCalendarRequest{From: &date, To: &date}}},
+		{"/indices/bars/daily", indexPriceParameters{IndexPriceRequest: IndexPriceRequest{Code: &indexCode, From: &date, To: &date}}},
+		{"/indices/bars/daily/topix", topixPriceParameters{TopixPriceRequest: TopixPriceRequest{From: &date, To: &date}}},
+		{"/derivatives/bars/daily/options/225", indexOptionPriceParameters{IndexOptionPriceRequest: IndexOptionPriceRequest{Date: date}}},
+	}
+	entitlements := make(map[string]bool, len(probes))
+	for _, probe := range probes {
+		ok, err := c.probe(ctx, probe.path, probe.params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to probe %s: %w", probe.path, err)
+		}
+		entitlements[probe.path] = ok
+	}
+	return entitlements, nil
+}
+
+func (c *Client) probe(ctx context.Context, urlPath string, param parameters) (bool, error) {
+	resp, err := c.sendRequest(ctx, urlPath, param)
+	if err != nil {
+		return false, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	defer func() {
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
+	}()
+	switch resp.StatusCode {
+	case 200:
+		return true, nil
+	case 403:
+		return false, nil
+	default:
+		return false, c.handleErrorResponse(resp)
+	}
+}
+
+// SchemaError reports that an endpoint's records no longer contain the compact JSON keys the
+// unmarshalers expect, which would otherwise silently decode as zero values.
+type SchemaError struct {
+	// Path is the endpoint path that was checked.
+	Path string
+	// MissingKeys are the expected keys absent from the sampled record.
+	MissingKeys []string
+}
+
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("schema drift detected on %s: missing keys %v", e.Path, e.MissingKeys)
+}
+
+// VerifySchema fetches a sample record from a few endpoints and checks that the compact JSON keys used by the
+// unmarshalers (e.g., "CoName", "S17") are still present. It returns a [SchemaError] for each endpoint whose
+// schema appears to have drifted, joined into a single error, or nil if everything matches.
+func (c *Client) VerifySchema(ctx context.Context) error {
+	date := time.Now().In(jst).Add(-entitlementProbeAge)
+	to := date.Format(dateLayout)
+	from := date.AddDate(0, 0, -14).Format(dateLayout)
+	code := "13010"
+	checks := []struct {
+		path   string
+		params parameters
+		keys   []string
+	}{
+		{
+			"/equities/master",
+			issueInformationParameters{IssueInformationRequest: IssueInformationRequest{Code: &code}},
+			[]string{"Date", "Code", "CoName", "CoNameEn", "S17", "S17Nm", "S33", "S33Nm", "ScaleCat", "Mkt", "MktNm"},
+		},
+		{
+			"/markets/calendar",
+			tradingCalendarParameters{TradingCalendarRequest{From: &from, To: &to}},
+			[]string{"Date", "HolDiv"},
+		},
+		{
+			"/indices/bars/daily/topix",
+			topixPriceParameters{TopixPriceRequest: TopixPriceRequest{From: &from, To: &to}},
+			[]string{"Date", "O", "H", "L", "C"},
+		},
+	}
+	var errs []error
+	for _, check := range checks {
+		record, err := c.sampleRecord(ctx, check.path, check.params)
+		if err != nil {
+			return fmt.Errorf("failed to sample %s: %w", check.path, err)
+		}
+		var missing []string
+		for _, key := range check.keys {
+			if _, ok := record[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, SchemaError{Path: check.path, MissingKeys: missing})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sampleRecord returns the first record of the first page of an endpoint as raw key/value pairs.
+func (c *Client) sampleRecord(ctx context.Context, urlPath string, param parameters) (map[string]json.RawMessage, error) {
+	var r struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	resp, err := c.sendRequest(ctx, urlPath, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	if len(r.Data) == 0 {
+		return nil, errors.New("no records returned")
+	}
+	return r.Data[0], nil
+}
+
+type dateParameters struct {
+	Date          string
+	PaginationKey *string
+}
+
+func (p dateParameters) values() (url.Values, error) {
+	if err := checkDate("date", &p.Date); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("date", p.Date)
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+// ExpectedRecordCount returns the number of records endpoint (a path such as "/equities/bars/daily")
+// returns for a date query (YYYY-MM-DD), following pagination. Records are counted while streaming the
+// response and are not decoded into typed structs, so it is cheap enough for daily ingestion monitoring.
+func (c *Client) ExpectedRecordCount(ctx context.Context, endpoint, date string) (int, error) {
+	count := 0
+	err := paginate(ctx, c, func(ctx context.Context, paginationKey *string) (streamedPage[json.RawMessage], error) {
+		params := dateParameters{Date: date, PaginationKey: paginationKey}
+		return streamPage(ctx, c, endpoint, params, func(json.RawMessage) error {
+			count++
+			return nil
+		})
+	}, func(streamedPage[json.RawMessage]) {})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// fetchAllPages fetches all pages of a paginated API endpoint.
+// If records were skipped, the decoded data is returned together with a [MalformedRecordsError].
+func fetchAllPages[T any, R Response[T]](
 	ctx context.Context,
 	c *Client,
-	ch chan<- T,
 	fetchPage func(ctx context.Context, paginationKey *string) (R, error),
+) ([]T, error) {
+	data := make([]T, 0)
+	err := paginate(ctx, c, fetchPage, func(resp R) {
+		data = append(data, resp.Items()...)
+	})
+	if err != nil && !errors.As(err, &MalformedRecordsError{}) {
+		return nil, err
+	}
+	if c.stableOrder {
+		sortStable(data)
+	}
+	return data, err
+}
+
+// fetchAllPagesWithChannel fetches all pages and sends each item to ch as soon as it is decoded.
+// Pages are decoded with streamPage, so only one record is held in memory at a time.
+// ch is always closed when the function returns, and sends are abandoned once ctx is done.
+func fetchAllPagesWithChannel[T any](
+	ctx context.Context,
+	c *Client,
+	ch chan<- T,
+	fetchPage func(ctx context.Context, paginationKey *string, emit func(T) error) (streamedPage[T], error),
 ) error {
-	var paginationKey *string
-	ctx, cancel := context.WithTimeout(ctx, c.loopTimeout)
-	defer cancel()
-	for {
-		resp, err := fetchPage(ctx, paginationKey)
+	defer close(ch)
+	send := func(item T) error {
+		select {
+		case ch <- item:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return paginate(ctx, c, func(ctx context.Context, paginationKey *string) (streamedPage[T], error) {
+		return fetchPage(ctx, paginationKey, send)
+	}, func(streamedPage[T]) {})
+}
+
+// errStopIteration is returned by the emit callback of fetchAllPagesSeq when the consumer stops ranging,
+// ending the pagination loop without fetching further pages.
+var errStopIteration = errors.New("iteration stopped")
+
+// fetchAllPagesSeq is the iterator counterpart of fetchAllPagesWithChannel: it yields each item as it is
+// decoded, with the same retries and loop timeout, and yields a final zero item with the error if fetching
+// fails. No goroutine is started, and breaking out of the loop stops the fetch after the current record.
+func fetchAllPagesSeq[T any](
+	ctx context.Context,
+	c *Client,
+	fetchPage func(ctx context.Context, paginationKey *string, emit func(T) error) (streamedPage[T], error),
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		emit := func(item T) error {
+			if !yield(item, nil) {
+				return errStopIteration
+			}
+			return nil
+		}
+		err := paginate(ctx, c, func(ctx context.Context, paginationKey *string) (streamedPage[T], error) {
+			return fetchPage(ctx, paginationKey, emit)
+		}, func(streamedPage[T]) {})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// streamedPage is the Response of a page whose records were passed to a callback while decoding
+// instead of being collected, so Items is always empty.
+type streamedPage[T any] struct {
+	count int
+	next  *string
+	errs  []RecordError
+}
+
+func (p streamedPage[T]) Items() []T                      { return nil }
+func (p streamedPage[T]) NextPageKey() *string            { return p.next }
+func (p streamedPage[T]) malformedRecords() []RecordError { return p.errs }
+func (p streamedPage[T]) recordCount() int                { return p.count }
+
+// streamPage sends a request and decodes the "data" array of the response one record at a time,
+// passing each record to emit, so the page is never materialized as a slice.
+func streamPage[T any](ctx context.Context, c *Client, urlPath string, params parameters, emit func(T) error) (streamedPage[T], error) {
+	var p streamedPage[T]
+	resp, err := c.sendRequest(ctx, urlPath, params)
+	if err != nil {
+		return p, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	defer func() {
+		if clsErr := resp.Body.Close(); clsErr != nil {
+			c.logger.Warn("failed to close response body", "error", clsErr)
+		}
+	}()
+	if resp.StatusCode != 200 {
+		return p, c.handleErrorResponse(resp)
+	}
+	bodyReader, err := responseReader(resp)
+	if err != nil {
+		return p, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	defer bodyReader.Close()
+	var emitErr error
+	err = p.decode(json.NewDecoder(bodyReader), c.skipMalformedRecords, func(item T) error {
+		emitErr = emit(item)
+		return emitErr
+	})
+	if emitErr != nil {
+		return p, emitErr
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return p, nil
+}
+
+func (p *streamedPage[T]) decode(dec *json.Decoder, skipMalformed bool, emit func(T) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
 		if err != nil {
-			if errors.As(err, &InternalServerError{}) {
-				slog.Warn("Retrying HTTP request", "error", err.Error())
-				time.Sleep(c.retryInterval)
+			return err
+		}
+		switch key {
+		case "data":
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if tok == nil {
 				continue
 			}
-			return err
+			if tok != json.Delim('[') {
+				return fmt.Errorf("unexpected token %v for data", tok)
+			}
+			for i := 0; dec.More(); i++ {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return err
+				}
+				var item T
+				if err := json.Unmarshal(raw, &item); err != nil {
+					if !skipMalformed {
+						return err
+					}
+					p.errs = append(p.errs, RecordError{Index: i, Raw: raw, Err: err})
+					continue
+				}
+				p.count++
+				if err := emit(item); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "pagination_key":
+			if err := dec.Decode(&p.next); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("unexpected token %v, want %v", tok, delim)
+	}
+	return nil
+}
+
+// maxRangeDays is the widest from/to span, in days, that the range-splitting methods (StockPrice, IndexPrice,
+// TopixPrices, MarginTradingOutstanding, ShortSellingValue, and Breakdown) request in a single call.
+// The API does not publish per-endpoint range limits; one year keeps each request to a size every plan
+// serves without a range error, and EstimateRequests counts one chunk per year accordingly.
+const maxRangeDays = 365
+
+// earliestDataDate is the first trading day of the history J-Quants serves on any plan. A range with no
+// From is walked back no further than this.
+const earliestDataDate = "2008-05-07"
+
+// fetchDateRangeInChunks splits the inclusive [from, to] range into consecutive sub-ranges spanning at most
+// maxDays days and calls fetch for each, concatenating the results in order.
+// A nil to means today (JST). A nil from walks back from to, one sub-range at a time, until a sub-range
+// falls outside the plan's coverage or reaches earliestDataDate. Empty sub-ranges do not end the walk, since
+// a code may have gaps of more than a year (suspension, delisting) before its earlier history.
+// If both bounds are nil or maxDays is not positive, the range is fetched in a single call.
+func fetchDateRangeInChunks[T any](from, to *string, maxDays int, fetch func(from, to *string) ([]T, error)) ([]T, error) {
+	if (from == nil && to == nil) || maxDays <= 0 {
+		return fetch(from, to)
+	}
+	if err := checkDateRange(nil, from, to); err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return fetchDateRangeBackward(*to, maxDays, fetch)
+	}
+	start, _ := time.Parse(dateLayout, *from)
+	today := timeNow().In(jst).Format(dateLayout)
+	end, _ := time.Parse(dateLayout, today)
+	if to != nil {
+		end, _ = time.Parse(dateLayout, *to)
+	} else if *from > today {
+		return fetch(from, to)
+	}
+	data := make([]T, 0)
+	var malformed []RecordError
+	for !start.After(end) {
+		chunkEnd := start.AddDate(0, 0, maxDays-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		chunkFrom, chunkTo := start.Format(dateLayout), chunkEnd.Format(dateLayout)
+		items, err := fetch(&chunkFrom, &chunkTo)
+		var malformedErr MalformedRecordsError
+		if errors.As(err, &malformedErr) {
+			malformed = append(malformed, malformedErr.Records...)
+		} else if err != nil {
+			return nil, err
 		}
-		for _, item := range resp.Items() {
-			ch <- item
+		data = append(data, items...)
+		start = chunkEnd.AddDate(0, 0, 1)
+	}
+	if len(malformed) > 0 {
+		return data, MalformedRecordsError{Records: malformed}
+	}
+	return data, nil
+}
+
+// fetchDateRangeBackward fetches the sub-ranges of at most maxDays days ending on to, newest first, until one
+// is refused as outside the plan's coverage or earliestDataDate is reached, and returns the records in
+// chronological order of the sub-ranges.
+func fetchDateRangeBackward[T any](to string, maxDays int, fetch func(from, to *string) ([]T, error)) ([]T, error) {
+	if to < earliestDataDate {
+		return fetch(nil, &to)
+	}
+	end, _ := time.Parse(dateLayout, to)
+	earliest, _ := time.Parse(dateLayout, earliestDataDate)
+	var chunks [][]T
+	var malformed []RecordError
+	for !end.Before(earliest) {
+		start := end.AddDate(0, 0, 1-maxDays)
+		if start.Before(earliest) {
+			start = earliest
 		}
-		paginationKey = resp.NextPageKey()
-		if paginationKey == nil {
+		chunkFrom, chunkTo := start.Format(dateLayout), end.Format(dateLayout)
+		items, err := fetch(&chunkFrom, &chunkTo)
+		var malformedErr MalformedRecordsError
+		var forbidden Forbidden
+		if errors.As(err, &malformedErr) {
+			malformed = append(malformed, malformedErr.Records...)
+		} else if errors.As(err, &forbidden) && forbidden.IsPlanRestriction() && len(chunks) > 0 {
 			break
+		} else if err != nil {
+			return nil, err
 		}
+		chunks = append(chunks, items)
+		end = start.AddDate(0, 0, -1)
 	}
-	close(ch)
-	return nil
+	data := make([]T, 0)
+	for _, items := range slices.Backward(chunks) {
+		data = append(data, items...)
+	}
+	if len(malformed) > 0 {
+		return data, MalformedRecordsError{Records: malformed}
+	}
+	return data, nil
 }
@@ -0,0 +1,556 @@
+package jquants
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestFetchDateRangeInChunks(t *testing.T) {
+	from, to := "2023-01-01", "2024-12-31"
+	var chunks [][2]string
+	_, err := fetchDateRangeInChunks(&from, &to, 365, func(from, to *string) ([]int, error) {
+		chunks = append(chunks, [2]string{*from, *to})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to fetch in chunks: %v", err)
+	}
+	want := [][2]string{
+		{"2023-01-01", "2023-12-31"},
+		{"2024-01-01", "2024-12-30"},
+		{"2024-12-31", "2024-12-31"},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("Unexpected chunks: got %v, want %v", chunks, want)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("Unexpected chunk %d: got %v, want %v", i, chunks[i], want[i])
+		}
+	}
+}
+
+func TestFetchDateRangeInChunks_OpenBounds(t *testing.T) {
+	// Without From, sub-ranges are walked back from To past empty years down to earliestDataDate.
+	to := "2012-12-31"
+	var chunks [][2]string
+	data, err := fetchDateRangeInChunks(nil, &to, 365, func(from, to *string) ([]string, error) {
+		chunks = append(chunks, [2]string{*from, *to})
+		if *from > "2010-01-01" && *from < "2012-01-01" {
+			return nil, nil
+		}
+		return []string{*from}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to fetch in chunks: %v", err)
+	}
+	want := [][2]string{
+		{"2012-01-02", "2012-12-31"},
+		{"2011-01-02", "2012-01-01"},
+		{"2010-01-02", "2011-01-01"},
+		{"2009-01-02", "2010-01-01"},
+		{"2008-05-07", "2009-01-01"},
+	}
+	if !slices.Equal(chunks, want) {
+		t.Errorf("Unexpected chunks: got %v, want %v", chunks, want)
+	}
+	if !slices.Equal(data, []string{"2008-05-07", "2009-01-02", "2012-01-02"}) {
+		t.Errorf("Expected records in chronological order across the empty years: %v", data)
+	}
+
+	// A sub-range outside the plan's coverage ends the walk instead of failing it.
+	to = "2024-12-31"
+	calls := 0
+	data, err = fetchDateRangeInChunks(nil, &to, 365, func(from, to *string) ([]string, error) {
+		if calls++; calls > 1 {
+			return nil, Forbidden{HTTPError{403, "forbidden", errors.New("Your subscription covers the following dates: 2024-01-01 ~ 2024-12-31.")}}
+		}
+		return []string{*from}, nil
+	})
+	if err != nil || len(data) != 1 {
+		t.Errorf("Expected the covered sub-range only, got %v, %v", data, err)
+	}
+
+	// Without To, the range ends today.
+	from := time.Now().In(jst).AddDate(0, 0, -400).Format(dateLayout)
+	chunks = nil
+	if _, err := fetchDateRangeInChunks(&from, nil, 365, func(from, to *string) ([]int, error) {
+		chunks = append(chunks, [2]string{*from, *to})
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("Failed to fetch in chunks: %v", err)
+	}
+	if today := time.Now().In(jst).Format(dateLayout); len(chunks) != 2 || chunks[0][0] != from || chunks[1][1] != today {
+		t.Errorf("Expected two sub-ranges ending today (%s), got %v", today, chunks)
+	}
+}
+
+func TestFetchDateRangeInChunks_TodayBeforeUTCMidnight(t *testing.T) {
+	// 2024-06-10 08:00 JST is still 2024-06-09 in UTC; a From of today must still be fetched.
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 6, 10, 8, 0, 0, 0, jst) }
+	from := "2024-06-10"
+	var chunks [][2]string
+	if _, err := fetchDateRangeInChunks(&from, nil, 365, func(from, to *string) ([]int, error) {
+		chunks = append(chunks, [2]string{*from, *to})
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("Failed to fetch in chunks: %v", err)
+	}
+	if want := [][2]string{{"2024-06-10", "2024-06-10"}}; !slices.Equal(chunks, want) {
+		t.Errorf("Unexpected chunks: got %v, want %v", chunks, want)
+	}
+}
+
+type intPage struct {
+	items []int
+	next  *string
+}
+
+func (p intPage) Items() []int         { return p.items }
+func (p intPage) NextPageKey() *string { return p.next }
+
+func TestFetchAllPages_LoopTimeout(t *testing.T) {
+	client := NewClient(BaseURL, "", WithLoopTimeout(10*time.Millisecond))
+	key := "next"
+	_, err := fetchAllPages(t.Context(), client, func(ctx context.Context, paginationKey *string) (intPage, error) {
+		if paginationKey == nil {
+			return intPage{items: []int{1}, next: &key}, nil
+		}
+		<-ctx.Done()
+		return intPage{}, fmt.Errorf("failed to send GET request: %w", ctx.Err())
+	})
+	var loopErr LoopTimeoutError
+	if !errors.As(err, &loopErr) {
+		t.Fatalf("Expected LoopTimeoutError, got %v", err)
+	}
+	if loopErr.PagesFetched != 1 || loopErr.LastPaginationKey == nil || *loopErr.LastPaginationKey != key {
+		t.Errorf("Unexpected loop timeout error: %+v", loopErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap context.DeadlineExceeded: %v", err)
+	}
+}
+
+func TestWarningHandler(t *testing.T) {
+	var warnings []Warning
+	client := NewClient(BaseURL, "", WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	key := "next"
+	data, err := fetchAllPages(t.Context(), client, func(ctx context.Context, paginationKey *string) (intPage, error) {
+		if paginationKey == nil {
+			return intPage{next: &key}, nil
+		}
+		return intPage{items: []int{1}}, nil
+	})
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Unexpected result: data=%v, err=%v", data, err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != WarningEmptyPage || warnings[0].Context["page"] != "1" {
+		t.Errorf("Unexpected warnings: %+v", warnings)
+	}
+}
+
+func TestStreamedPage_Decode(t *testing.T) {
+	body := `{"data":[1,"x",3],"other":{"a":[1]},"pagination_key":"next"}`
+	var got []int
+	var p streamedPage[int]
+	err := p.decode(json.NewDecoder(strings.NewReader(body)), true, func(i int) error {
+		got = append(got, i)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to decode page: %v", err)
+	}
+	if !slices.Equal(got, []int{1, 3}) || p.recordCount() != 2 {
+		t.Errorf("Unexpected records: %v", got)
+	}
+	if p.next == nil || *p.next != "next" || len(p.errs) != 1 || p.errs[0].Index != 1 {
+		t.Errorf("Unexpected page: %+v", p)
+	}
+}
+
+func TestEndpointTimeout(t *testing.T) {
+	client := NewClient(BaseURL, "", WithEndpointTimeouts(map[string]time.Duration{
+		"/equities":        time.Minute,
+		"/equities/master": time.Second,
+	}))
+	if d, ok := client.endpointTimeout("/equities/master"); !ok || d != time.Second {
+		t.Errorf("Unexpected timeout for master: %v", d)
+	}
+	if d, ok := client.endpointTimeout("/equities/bars/daily"); !ok || d != time.Minute {
+		t.Errorf("Unexpected timeout for daily bars: %v", d)
+	}
+	if _, ok := client.endpointTimeout("/markets/calendar"); ok {
+		t.Error("Expected no timeout for unmatched path")
+	}
+}
+
+func TestFetchAllPages_ServiceUnavailable(t *testing.T) {
+	client := NewClient(BaseURL, "", WithRetryInterval(time.Millisecond))
+	attempts := 0
+	data, err := fetchAllPages(t.Context(), client, func(ctx context.Context, paginationKey *string) (intPage, error) {
+		attempts++
+		if attempts < 3 {
+			return intPage{}, ServiceUnavailable{HTTPError: HTTPError{503, "service unavailable", errors.New("maintenance")}}
+		}
+		return intPage{items: []int{1}}, nil
+	})
+	if err != nil || len(data) != 1 || attempts != 3 {
+		t.Errorf("Unexpected result: data=%v, attempts=%d, err=%v", data, attempts, err)
+	}
+}
+
+func TestMaintenanceEnd(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	until := maintenanceEnd(resp, errors.New("under maintenance until 2024/01/06 18:00"))
+	want := time.Date(2024, 1, 6, 18, 0, 0, 0, jst)
+	if until == nil || !until.Equal(want) {
+		t.Errorf("Unexpected maintenance end: got %v, want %v", until, want)
+	}
+	resp.Header.Set("Retry-After", "120")
+	if until := maintenanceEnd(resp, nil); until == nil || time.Until(*until) < time.Minute {
+		t.Errorf("Unexpected maintenance end from Retry-After: %v", until)
+	}
+	if until := maintenanceEnd(&http.Response{Header: http.Header{}}, errors.New("maintenance")); until != nil {
+		t.Errorf("Expected unknown maintenance end, got %v", until)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	code := "13010"
+	if err := ValidateRequest(StockPriceRequest{Code: &code}); err != nil {
+		t.Errorf("Unexpected error for valid request: %v", err)
+	}
+	if err := ValidateRequest(StockPriceRequest{}); err == nil {
+		t.Error("Expected error for request without code or date")
+	}
+	if err := ValidateRequest(code); err == nil {
+		t.Error("Expected error for unsupported request type")
+	}
+}
+
+type flakyTransport struct {
+	calls int
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls == 1 {
+		return nil, io.EOF
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestSendRequest_RetriesClosedConnection(t *testing.T) {
+	transport := &flakyTransport{}
+	client := NewClient(BaseURL, "test", WithHTTPClient(&http.Client{Transport: transport}))
+	resp, err := client.sendRequest(t.Context(), "/markets/calendar", tradingCalendarParameters{})
+	if err != nil {
+		t.Fatalf("Expected retry to succeed: %v", err)
+	}
+	resp.Body.Close()
+	if transport.calls != 2 {
+		t.Errorf("Unexpected number of attempts: %d", transport.calls)
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	var order []string
+	middleware := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" before")
+				resp, err := next.RoundTrip(req)
+				order = append(order, name+" after")
+				return resp, err
+			})
+		}
+	}
+	transport := &flakyTransport{calls: 1}
+	httpClient := &http.Client{Transport: transport}
+	client := NewClient(BaseURL, "test", WithHTTPClient(httpClient), WithMiddleware(middleware("outer")), WithMiddleware(middleware("inner")))
+	resp, err := client.sendRequest(t.Context(), "/markets/calendar", tradingCalendarParameters{})
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Unexpected middleware order: got %v, want %v", order, want)
+	}
+	if transport.calls != 2 {
+		t.Errorf("Expected the request to reach the wrapped transport")
+	}
+	if httpClient.Transport != transport {
+		t.Errorf("Expected the caller's HTTP client to be left unmodified")
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	client := NewClient(BaseURL, "test", WithHTTPClient(&http.Client{Transport: &flakyTransport{}}), WithLogger(logger))
+	resp, err := client.sendRequest(t.Context(), "/markets/calendar", tradingCalendarParameters{})
+	if err != nil {
+		t.Fatalf("Expected retry to succeed: %v", err)
+	}
+	resp.Body.Close()
+	if !strings.Contains(buf.String(), "level=WARN") {
+		t.Errorf("Expected retry warning in injected logger, got %q", buf.String())
+	}
+
+	client = NewClient(BaseURL, "test", WithHTTPClient(&http.Client{Transport: &flakyTransport{}}), WithLogger(nil))
+	resp, err = client.sendRequest(t.Context(), "/markets/calendar", tradingCalendarParameters{})
+	if err != nil {
+		t.Fatalf("Expected retry to succeed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestDecodeResponse_ContentEncoding(t *testing.T) {
+	body := `{"data":[{"Date":"2024-05-10","Code":"72030","CoName":"トヨタ自動車"}]}`
+	for _, encoding := range []string{"", "identity", "gzip", "GZIP"} {
+		t.Run(encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.EqualFold(encoding, "gzip") {
+					if encoding != "" {
+						w.Header().Set("Content-Encoding", encoding)
+					}
+					io.WriteString(w, body)
+					return
+				}
+				w.Header().Set("Content-Encoding", encoding)
+				gz := gzip.NewWriter(w)
+				io.WriteString(gz, body)
+				gz.Close()
+			}))
+			defer server.Close()
+			client := NewClient(server.URL, "test")
+			announcements, err := client.EarningsAnnouncement(t.Context(), EarningsAnnouncementRequest{})
+			if err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(announcements) != 1 || announcements[0].Code != "72030" {
+				t.Errorf("Unexpected announcements: %+v", announcements)
+			}
+		})
+	}
+}
+
+func TestNewTestClient(t *testing.T) {
+	var apiKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKeys = append(apiKeys, r.Header.Get("x-api-key"))
+		io.WriteString(w, `{"data":[]}`)
+	}))
+	defer server.Close()
+	t.Setenv(APIKeyEnv, "")
+
+	client := NewTestClient(server.URL, "test", server.Client())
+	if _, err := client.EarningsAnnouncement(t.Context(), EarningsAnnouncementRequest{}); err != nil {
+		t.Fatalf("Failed to query mock server: %v", err)
+	}
+	client = NewClient(BaseURL, "", WithBaseURL(server.URL), WithAPIKey("override"))
+	if _, err := client.EarningsAnnouncement(t.Context(), EarningsAnnouncementRequest{}); err != nil {
+		t.Fatalf("Failed to query mock server: %v", err)
+	}
+	if !slices.Equal(apiKeys, []string{"test", "override"}) {
+		t.Errorf("Unexpected API keys: %v", apiKeys)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"internal server error", fmt.Errorf("wrapped: %w", InternalServerError{HTTPError{500, "internal server error", errors.New("oops")}}), true},
+		{"service unavailable", ServiceUnavailable{HTTPError: HTTPError{503, "service unavailable", errors.New("maintenance")}}, true},
+		{"too many requests", TooManyRequests{HTTPError: HTTPError{429, "too many requests", errors.New("slow down")}}, true},
+		{"transient forbidden", Forbidden{HTTPError{403, "forbidden", errors.New("invalid key")}}, true},
+		{"plan restriction", Forbidden{HTTPError{403, "forbidden", errors.New("not covered by your subscription")}}, false},
+		{"bad request", BadRequest{HTTPError{400, "bad request", io.ErrUnexpectedEOF}}, false},
+		{"connection closed", fmt.Errorf("failed to send GET request: %w", io.EOF), true},
+		{"canceled", context.Canceled, false},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"wrapped deadline exceeded", fmt.Errorf("failed to send GET request: %w", context.DeadlineExceeded), false},
+		{"loop timeout", LoopTimeoutError{Timeout: time.Second, Err: context.DeadlineExceeded}, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Entitlements(t *testing.T) {
+	client := setupClient(t)
+	entitlements, err := client.Entitlements(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to get entitlements: %v", err)
+	}
+	if !entitlements["/equities/master"] {
+		t.Error("Expected /equities/master to be accessible")
+	}
+}
+
+func TestClient_ExpectedRecordCount(t *testing.T) {
+	client := setupClient(t)
+	count, err := client.ExpectedRecordCount(t.Context(), "/equities/bars/daily", "2025-01-06")
+	if err != nil {
+		t.Fatalf("Failed to count records: %v", err)
+	}
+	if count == 0 {
+		t.Error("Expected records for a trading day")
+	}
+}
+
+func TestRecords_UnmarshalJSON(t *testing.T) {
+	body := `{"data":[{"Date":"2025-01-06","Code":"13010","UL":"0","LL":"0"},{"Date":"2025-01-07","Code":"13010","UL":"x","LL":"0"}],"pagination_key":"next"}`
+
+	var strict pageResponse[StockPrice]
+	if err := json.Unmarshal([]byte(body), &strict); err == nil {
+		t.Error("Expected strict decoding to fail on a malformed record")
+	}
+
+	var lenient pageResponse[StockPrice]
+	lenient.Data.skipMalformed = true
+	if err := json.Unmarshal([]byte(body), &lenient); err != nil {
+		t.Fatalf("Failed to decode leniently: %v", err)
+	}
+	if len(lenient.Items()) != 1 || lenient.Items()[0].Date != "2025-01-06" {
+		t.Errorf("Unexpected items: %+v", lenient.Items())
+	}
+	if errs := lenient.malformedRecords(); len(errs) != 1 || errs[0].Index != 1 {
+		t.Errorf("Unexpected malformed records: %+v", errs)
+	}
+	if key := lenient.NextPageKey(); key == nil || *key != "next" {
+		t.Errorf("Unexpected pagination key: %v", key)
+	}
+}
+
+func TestClient_VerifySchema(t *testing.T) {
+	client := setupClient(t)
+	if err := client.VerifySchema(t.Context()); err != nil {
+		t.Errorf("Schema verification failed: %v", err)
+	}
+}
+
+func TestForbidden_IsPlanRestriction(t *testing.T) {
+	plan := Forbidden{HTTPError{403, "forbidden", errors.New("Your subscription covers the following dates: 2023-01-01 ~ 2025-01-01.")}}
+	if !plan.IsPlanRestriction() {
+		t.Error("Expected plan restriction")
+	}
+	transient := Forbidden{HTTPError{403, "forbidden", errors.New("Forbidden")}}
+	if transient.IsPlanRestriction() {
+		t.Error("Expected transient forbidden error")
+	}
+}
+
+func TestContextWithAPIKey(t *testing.T) {
+	client := NewClient(BaseURL, "default")
+	if key, _ := client.requestAPIKey(t.Context()); key != "default" {
+		t.Errorf("Unexpected default API key: %q", key)
+	}
+	ctx := ContextWithAPIKey(t.Context(), "tenant")
+	if key, _ := client.requestAPIKey(ctx); key != "tenant" {
+		t.Errorf("Unexpected overridden API key: %q", key)
+	}
+}
+
+func TestContextCredential_Cache(t *testing.T) {
+	var auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("x-api-key") + r.Header.Get("Authorization")
+		auths = append(auths, auth)
+		if auth == "Bearer revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"The incoming token is invalid or expired."}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"Date":"2024-01-04","HolDiv":"1"}],"auth":%q}`, auth)
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "default", nil, WithCache(NewMemoryCache(10)))
+	from, to := "2024-01-04", "2024-01-04"
+	req := TradingCalendarRequest{From: &from, To: &to}
+	contexts := []context.Context{
+		t.Context(),
+		ContextWithAPIKey(t.Context(), "tenant"),
+		ContextWithIDToken(t.Context(), "token"),
+		ContextWithIDToken(ContextWithAPIKey(t.Context(), "tenant"), "token"),
+		t.Context(),
+	}
+	for _, ctx := range contexts {
+		if _, err := client.TradingCalendar(ctx, req); err != nil {
+			t.Fatalf("Failed to get trading calendar: %v", err)
+		}
+	}
+	if want := []string{"default", "tenant", "Bearer token"}; !slices.Equal(auths, want) {
+		t.Errorf("Expected one request per credential and cache hits otherwise: got %v, want %v", auths, want)
+	}
+
+	// An overriding ID token is not renewed with the client's credentials.
+	_, err := client.TradingCalendar(ContextWithIDToken(t.Context(), "revoked"), req)
+	var unauthorized Unauthorized
+	if !errors.As(err, &unauthorized) {
+		t.Errorf("Expected Unauthorized for a rejected overriding token, got %v", err)
+	}
+}
+
+func TestNoAPIKey(t *testing.T) {
+	t.Setenv(APIKeyEnv, "")
+	if _, err := NewClientFromEnv(); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("Expected ErrNoAPIKey from NewClientFromEnv, got %v", err)
+	}
+	transport := &flakyTransport{}
+	client := NewClient(BaseURL, "", WithHTTPClient(&http.Client{Transport: transport}))
+	if _, err := client.sendRequest(t.Context(), "/markets/calendar", tradingCalendarParameters{}); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("Expected ErrNoAPIKey at request time, got %v", err)
+	}
+	if transport.calls != 0 {
+		t.Errorf("Expected no HTTP request without an API key, got %d", transport.calls)
+	}
+	t.Setenv(APIKeyEnv, "env")
+	if key, err := client.requestAPIKey(t.Context()); err != nil || key != "env" {
+		t.Errorf("Expected the API key to be read from the environment at request time, got %q, %v", key, err)
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"default", nil, defaultMaxConcurrency},
+		{"rate limiter", []Option{WithRateLimiter(rate.NewLimiter(5, 1))}, 10},
+		{"slow rate limiter", []Option{WithRateLimiter(rate.NewLimiter(rate.Every(time.Minute), 1))}, 1},
+		{"explicit", []Option{WithRateLimiter(rate.NewLimiter(5, 1)), WithMaxConcurrency(3)}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClient(BaseURL, "", tt.opts...).concurrency(); got != tt.want {
+				t.Errorf("Unexpected concurrency: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
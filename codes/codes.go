@@ -1,5 +1,10 @@
 package codes
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	SectionTSE1st      = "TSE1st"
 	SectionTSE2nd      = "TSE2nd"
@@ -180,3 +185,23 @@ var Sector33Codes = []string{
 	Sector33Services,
 	Sector33Others,
 }
+
+// NormalizeCode converts a security code to the 5-character form the J-Quants API expects. Surrounding
+// whitespace is trimmed and letters are uppercased, a 4-character ticker such as "1301" (or an
+// alphanumeric one such as "130A") gets a trailing "0", and a 5-character code is returned as is.
+// Inputs that are not 4 or 5 digits or uppercase letters are rejected.
+func NormalizeCode(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if len(normalized) != 4 && len(normalized) != 5 {
+		return "", fmt.Errorf("invalid code %q: expected a 4- or 5-character security code", code)
+	}
+	for _, r := range normalized {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return "", fmt.Errorf("invalid code %q: expected a 4- or 5-character security code", code)
+		}
+	}
+	if len(normalized) == 4 {
+		normalized += "0"
+	}
+	return normalized, nil
+}
@@ -0,0 +1,152 @@
+package jquants
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// stockPriceCSVHeader is the header row written by WriteStockPriceCSV and expected by ReadStockPriceCSV.
+var stockPriceCSVHeader = []string{
+	"Date", "Code", "Open", "High", "Low", "Close", "UpperLimit", "LowerLimit", "Volume", "TurnoverValue",
+	"AdjustmentFactor", "AdjustedOpen", "AdjustedHigh", "AdjustedLow", "AdjustedClose", "AdjustedVolume",
+}
+
+// WriteStockPriceCSV writes prices to w as CSV: a header row with the StockPrice field names, then one row
+// per price with the unadjusted and adjusted columns. Nil prices and volumes are written as empty cells,
+// numbers exactly as the API sent them, and the limit flags as "true" or "false", so the output loads
+// directly into pandas (pd.read_csv) and back with [ReadStockPriceCSV].
+func WriteStockPriceCSV(w io.Writer, prices []StockPrice) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(stockPriceCSVHeader); err != nil {
+		return err
+	}
+	for _, p := range prices {
+		record := []string{
+			p.Date,
+			p.Code,
+			formatCSVNumber(p.Open),
+			formatCSVNumber(p.High),
+			formatCSVNumber(p.Low),
+			formatCSVNumber(p.Close),
+			strconv.FormatBool(p.UpperLimit),
+			strconv.FormatBool(p.LowerLimit),
+			formatCSVInt64(p.Volume),
+			formatCSVInt64(p.TurnoverValue),
+			p.AdjustmentFactor.String(),
+			formatCSVNumber(p.AdjustedOpen),
+			formatCSVNumber(p.AdjustedHigh),
+			formatCSVNumber(p.AdjustedLow),
+			formatCSVNumber(p.AdjustedClose),
+			formatCSVInt64(p.AdjustedVolume),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadStockPriceCSV reads prices written by [WriteStockPriceCSV]. The header row must match the one
+// WriteStockPriceCSV writes; empty cells become nil prices and volumes.
+func ReadStockPriceCSV(r io.Reader) ([]StockPrice, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(stockPriceCSVHeader)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing CSV header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(header, stockPriceCSVHeader) {
+		return nil, fmt.Errorf("unexpected CSV header %v", header)
+	}
+	var prices []StockPrice
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return prices, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		p, err := parseStockPriceCSV(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		prices = append(prices, p)
+	}
+}
+
+// parseStockPriceCSV converts a CSV record in stockPriceCSVHeader order to a StockPrice.
+func parseStockPriceCSV(record []string) (StockPrice, error) {
+	p := StockPrice{Date: record[0], Code: record[1]}
+	var errs []error
+	number := func(i int) *json.Number {
+		n, err := parseCSVNumber(record[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stockPriceCSVHeader[i], err))
+		}
+		return n
+	}
+	integer := func(i int) *int64 {
+		if record[i] == "" {
+			return nil
+		}
+		v, err := strconv.ParseInt(record[i], 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stockPriceCSVHeader[i], err))
+			return nil
+		}
+		return &v
+	}
+	boolean := func(i int) bool {
+		v, err := strconv.ParseBool(record[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stockPriceCSVHeader[i], err))
+		}
+		return v
+	}
+	p.Open, p.High, p.Low, p.Close = number(2), number(3), number(4), number(5)
+	p.UpperLimit, p.LowerLimit = boolean(6), boolean(7)
+	p.Volume, p.TurnoverValue = integer(8), integer(9)
+	if factor := number(10); factor != nil {
+		p.AdjustmentFactor = *factor
+	}
+	p.AdjustedOpen, p.AdjustedHigh, p.AdjustedLow, p.AdjustedClose = number(11), number(12), number(13), number(14)
+	p.AdjustedVolume = integer(15)
+	return p, errors.Join(errs...)
+}
+
+func formatCSVNumber(n *json.Number) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
+
+func formatCSVInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// parseCSVNumber returns nil for an empty cell and an error if s is not a JSON number.
+func parseCSVNumber(s string) (*json.Number, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal([]byte(s), &n); err != nil {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	return &n, nil
+}
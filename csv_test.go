@@ -0,0 +1,60 @@
+package jquants
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStockPriceCSV(t *testing.T) {
+	number := func(s string) *json.Number { n := json.Number(s); return &n }
+	integer := func(v int64) *int64 { return &v }
+	prices := []StockPrice{
+		{
+			Date: "2024-05-10", Code: "72030",
+			Open: number("3400.0"), High: number("3433.0"), Low: number("3372.0"), Close: number("3410.0"),
+			UpperLimit: true, Volume: integer(31209500), TurnoverValue: integer(106268574950),
+			AdjustmentFactor: "1.0",
+			AdjustedOpen:     number("3400.0"), AdjustedHigh: number("3433.0"), AdjustedLow: number("3372.0"), AdjustedClose: number("3410.0"),
+			AdjustedVolume: integer(31209500),
+		},
+		{Date: "2024-05-10", Code: "13020", AdjustmentFactor: "1.0"},
+	}
+	var buf bytes.Buffer
+	if err := WriteStockPriceCSV(&buf, prices); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Date,Code,Open,") {
+		t.Fatalf("Unexpected CSV:\n%s", buf.String())
+	}
+	if lines[2] != "2024-05-10,13020,,,,,false,false,,,1.0,,,,," {
+		t.Errorf("Expected empty cells for nil values: %s", lines[2])
+	}
+
+	got, err := ReadStockPriceCSV(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if !reflect.DeepEqual(got, prices) {
+		t.Errorf("Round trip mismatch:\ngot  %+v\nwant %+v", got, prices)
+	}
+}
+
+func TestReadStockPriceCSV_Invalid(t *testing.T) {
+	header := strings.Join(stockPriceCSVHeader, ",") + "\n"
+	tests := map[string]string{
+		"empty":      "",
+		"header":     "Date,Code\n",
+		"number":     header + "2024-05-10,72030,abc,,,,false,false,,,1,,,,,\n",
+		"volume":     header + "2024-05-10,72030,,,,,false,false,1.5,,1,,,,,\n",
+		"limit flag": header + "2024-05-10,72030,,,,,maybe,false,,,1,,,,,\n",
+	}
+	for name, input := range tests {
+		if _, err := ReadStockPriceCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package jquants
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DateOf returns t's calendar date in its own location as a YYYY-MM-DD string, for the date fields of the
+// request types (e.g., StockPriceRequest{From: jquants.DateOf(start)}). Convert t with t.In first if its
+// location is not the one whose date is meant; the API's dates are JST.
+func DateOf(t time.Time) *string {
+	date := t.Format(dateLayout)
+	return &date
+}
+
+// checkDate returns an error if date is set but is not a valid YYYY-MM-DD date, so that malformed dates are
+// rejected before a request is sent instead of by an opaque 400 from the API. field names the query parameter.
+func checkDate(field string, date *string) error {
+	if date == nil {
+		return nil
+	}
+	if _, err := time.Parse(dateLayout, *date); err != nil {
+		return fmt.Errorf("invalid %s %q: expected YYYY-MM-DD", field, *date)
+	}
+	return nil
+}
+
+// checkDateRange checks the date, from, and to parameters of a request with checkDate.
+func checkDateRange(date, from, to *string) error {
+	return errors.Join(checkDate("date", date), checkDate("from", from), checkDate("to", to))
+}
+
+// parseDate parses a required YYYY-MM-DD date as midnight JST.
+func parseDate(value string) (time.Time, error) {
+	t, err := unmarshalDate(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t == nil {
+		return time.Time{}, errors.New("date is empty")
+	}
+	return *t, nil
+}
+
+// parseOptionalDate parses an optional YYYY-MM-DD date as midnight JST, returning nil if value is nil or empty.
+func parseOptionalDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return unmarshalDate(*value)
+}
+
+// The ParsedDate methods return Date as a time.Time at midnight JST (Asia/Tokyo), so callers do not have to
+// parse the YYYY-MM-DD string themselves. They return an error if Date is empty or malformed.
+
+func (i IssueInformation) ParsedDate() (time.Time, error)         { return parseDate(i.Date) }
+func (p StockPrice) ParsedDate() (time.Time, error)               { return parseDate(p.Date) }
+func (p MorningSessionStockPrice) ParsedDate() (time.Time, error) { return parseDate(p.Date) }
+func (m MarginTradingOutstanding) ParsedDate() (time.Time, error) { return parseDate(m.Date) }
+func (v ShortSellingValue) ParsedDate() (time.Time, error)        { return parseDate(v.Date) }
+func (b Breakdown) ParsedDate() (time.Time, error)                { return parseDate(b.Date) }
+func (tc TradingCalendar) ParsedDate() (time.Time, error)         { return parseDate(tc.Date) }
+func (p IndexPrice) ParsedDate() (time.Time, error)               { return parseDate(p.Date) }
+func (p TopixPrice) ParsedDate() (time.Time, error)               { return parseDate(p.Date) }
+func (p IndexOptionPrice) ParsedDate() (time.Time, error)         { return parseDate(p.Date) }
+func (p FuturesPrice) ParsedDate() (time.Time, error)             { return parseDate(p.Date) }
+func (a EarningsAnnouncement) ParsedDate() (time.Time, error)     { return parseDate(a.Date) }
+
+// ParsedLastTradingDay returns LastTradingDay at midnight JST, or nil if it is not set.
+func (p IndexOptionPrice) ParsedLastTradingDay() (*time.Time, error) {
+	return parseOptionalDate(p.LastTradingDay)
+}
+
+// ParsedSpecialQuotationDay returns SpecialQuotationDay at midnight JST, or nil if it is not set.
+func (p IndexOptionPrice) ParsedSpecialQuotationDay() (*time.Time, error) {
+	return parseOptionalDate(p.SpecialQuotationDay)
+}
+
+// ParsedLastTradingDay returns LastTradingDay at midnight JST, or nil if it is not set.
+func (p FuturesPrice) ParsedLastTradingDay() (*time.Time, error) {
+	return parseOptionalDate(p.LastTradingDay)
+}
+
+// ParsedSpecialQuotationDay returns SpecialQuotationDay at midnight JST, or nil if it is not set.
+func (p FuturesPrice) ParsedSpecialQuotationDay() (*time.Time, error) {
+	return parseOptionalDate(p.SpecialQuotationDay)
+}
@@ -0,0 +1,71 @@
+package jquants
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsedDate(t *testing.T) {
+	got, err := StockPrice{Date: "2025-01-06"}.ParsedDate()
+	if err != nil {
+		t.Fatalf("Failed to parse date: %v", err)
+	}
+	if want := time.Date(2025, 1, 6, 0, 0, 0, 0, jst); !got.Equal(want) || got.Location() != jst {
+		t.Errorf("Unexpected date: got %v, want %v", got, want)
+	}
+	halfDay, err := TradingCalendar{Date: "2024-12-30", DayType: 2}.ParsedDate()
+	if err != nil || halfDay.Day() != 30 {
+		t.Errorf("Unexpected half-day date: %v, %v", halfDay, err)
+	}
+	if _, err := (IndexPrice{}).ParsedDate(); err == nil {
+		t.Error("Expected error for an empty date")
+	}
+	if _, err := (TopixPrice{Date: "20250106"}).ParsedDate(); err == nil {
+		t.Error("Expected error for a date not in YYYY-MM-DD format")
+	}
+}
+
+func TestParsedOptionalDates(t *testing.T) {
+	sqd := "2025-03-14"
+	option := IndexOptionPrice{SpecialQuotationDay: &sqd}
+	got, err := option.ParsedSpecialQuotationDay()
+	if err != nil || got == nil || !got.Equal(time.Date(2025, 3, 14, 0, 0, 0, 0, jst)) {
+		t.Errorf("Unexpected special quotation day: %v, %v", got, err)
+	}
+	if got, err := option.ParsedLastTradingDay(); got != nil || err != nil {
+		t.Errorf("Expected nil for an unset last trading day, got %v, %v", got, err)
+	}
+	invalid := "2025/03/13"
+	if _, err := (FuturesPrice{LastTradingDay: &invalid}).ParsedLastTradingDay(); err == nil {
+		t.Error("Expected error for a malformed last trading day")
+	}
+}
+
+func TestDateParameterValidation(t *testing.T) {
+	code := "13010"
+	for _, date := range []string{"2025/01/06", "20250106", "2025-1-6", "2025-02-30"} {
+		params := []parameters{
+			stockPriceParameters{StockPriceRequest: StockPriceRequest{Date: &date}},
+			stockPriceParameters{StockPriceRequest: StockPriceRequest{Code: &code, From: &date}},
+			marginTradingOutstandingParameters{MarginTradingOutstandingRequest: MarginTradingOutstandingRequest{Code: &code, To: &date}},
+			indexPriceParameters{IndexPriceRequest: IndexPriceRequest{Date: &date}},
+			tradingCalendarParameters{TradingCalendarRequest: TradingCalendarRequest{From: &date}},
+			shortSellingPositionsParameters{ShortSellingPositionsRequest: ShortSellingPositionsRequest{DisclosedDate: &date}},
+		}
+		for _, p := range params {
+			_, err := p.values()
+			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%q: expected YYYY-MM-DD", date)) {
+				t.Errorf("%T with %q: expected invalid date error, got %v", p, date, err)
+			}
+		}
+	}
+	from := DateOf(time.Date(2025, 1, 6, 23, 0, 0, 0, jst))
+	if *from != "2025-01-06" {
+		t.Errorf("Unexpected DateOf: %s", *from)
+	}
+	if _, err := (stockPriceParameters{StockPriceRequest: StockPriceRequest{Code: &code, From: from}}).values(); err != nil {
+		t.Errorf("Unexpected error for a valid date: %v", err)
+	}
+}
@@ -1,12 +1,18 @@
 package jquants
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
+	"slices"
 	"strconv"
+	"time"
+
+	"github.com/s-shiga/jquants-go/v2/codes"
 )
 
 // IssueInformation represents master data for a listed security.
@@ -38,6 +44,9 @@ type IssueInformation struct {
 	MarginCode *int8
 	// MarginName is the name of the margin trading classification.
 	MarginName *string
+
+	// unknownMarginCode holds a margin code that could not be parsed, so the client can log it.
+	unknownMarginCode string
 }
 
 func (ii *IssueInformation) UnmarshalJSON(b []byte) error {
@@ -74,62 +83,118 @@ func (ii *IssueInformation) UnmarshalJSON(b []byte) error {
 	ii.ScaleCategory = raw.ScaleCategory
 	ii.MarketCode = raw.MarketCode
 	ii.MarketName = raw.MarketCodeName
-	if raw.MarginCode != nil {
-		marginCode, err := strconv.ParseInt(*raw.MarginCode, 10, 8)
-		if err != nil {
-			return err
-		}
-		v := int8(marginCode)
-		ii.MarginCode = &v
-	}
+	ii.MarginCode, ii.unknownMarginCode = unmarshalMarginCode(raw.MarginCode)
 	ii.MarginName = raw.MarginCodeName
 	return nil
 }
 
+// unmarshalMarginCode parses the margin trading classification code.
+// Empty and unparsable values yield nil rather than failing the whole master fetch; the latter are also
+// returned as unknown so that sendIssueInformationRequest can log them.
+func unmarshalMarginCode(s *string) (*int8, string) {
+	if s == nil || *s == "" {
+		return nil, ""
+	}
+	marginCode, err := strconv.ParseInt(*s, 10, 8)
+	if err != nil {
+		return nil, *s
+	}
+	v := int8(marginCode)
+	return &v, ""
+}
+
 // IssueInformationRequest specifies filter parameters for the IssueInformation API.
+// At least one of Code, Date, or AllData must be set; AllData alone returns the latest data for all securities.
 type IssueInformationRequest struct {
 	// Code filters by security code. If nil, returns all securities.
 	Code *string
 	// Date filters by date in YYYY-MM-DD format. If nil, returns the latest data.
 	Date *string
+	// AllData explicitly requests every security when neither Code nor Date is set.
+	AllData bool
 }
 
 type issueInformationParameters struct {
 	IssueInformationRequest
+	PaginationKey *string
 }
 
 func (p issueInformationParameters) values() (url.Values, error) {
+	if err := checkDate("date", p.Date); err != nil {
+		return nil, err
+	}
+	if p.Code == nil && p.Date == nil && !p.AllData {
+		return nil, errors.New("code, date, or AllData is required")
+	}
 	v := url.Values{}
 	if p.Code != nil {
-		v.Add("code", *p.Code)
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
 	}
 	if p.Date != nil {
 		v.Add("date", *p.Date)
 	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
 	return v, nil
 }
 
 type issueInformationResponse struct {
-	Information []IssueInformation `json:"data"`
+	Data          records[IssueInformation] `json:"data"`
+	PaginationKey *string                   `json:"pagination_key"`
 }
 
-// IssueInformation retrieves master data for listed securities from the /equities/master endpoint.
-// It returns company information, sector classifications, and market details.
-func (c *Client) IssueInformation(ctx context.Context, req IssueInformationRequest) ([]IssueInformation, error) {
+func (r issueInformationResponse) Items() []IssueInformation       { return r.Data.items }
+func (r issueInformationResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r issueInformationResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendIssueInformationRequest(ctx context.Context, params issueInformationParameters) (issueInformationResponse, error) {
 	var r issueInformationResponse
-	params := issueInformationParameters{req}
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/equities/master", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send GET request: %w", err)
+		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
-
 	if resp.StatusCode != 200 {
-		return nil, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
-		return nil, fmt.Errorf("failed to decode HTTP response: %w", err)
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	for _, ii := range r.Data.items {
+		if ii.unknownMarginCode != "" {
+			c.logger.Warn("ignoring unknown margin code", "code", ii.Code, "margin_code", ii.unknownMarginCode)
+		}
 	}
-	return r.Information, nil
+	return r, nil
+}
+
+// IssueInformation retrieves master data for listed securities from the /equities/master endpoint.
+// It returns company information, sector classifications, and market details.
+// It follows pagination keys so that no rows are lost if the master spans several pages.
+func (c *Client) IssueInformation(ctx context.Context, req IssueInformationRequest) ([]IssueInformation, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (issueInformationResponse, error) {
+		params := issueInformationParameters{IssueInformationRequest: req, PaginationKey: paginationKey}
+		return c.sendIssueInformationRequest(ctx, params)
+	})
+}
+
+// ActiveCodes returns the security codes present in the /equities/master snapshot for the given date (YYYY-MM-DD).
+// It is intended as the universe input for the multi-code fetch helpers.
+func (c *Client) ActiveCodes(ctx context.Context, date string) ([]string, error) {
+	issues, err := c.IssueInformation(ctx, IssueInformationRequest{Date: &date})
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes, nil
 }
 
 // StockPrice represents daily OHLCV (Open, High, Low, Close, Volume) data for a security.
@@ -179,14 +244,14 @@ func (sp *StockPrice) UnmarshalJSON(b []byte) error {
 		Close            *json.Number `json:"C"`
 		UpperLimit       string       `json:"UL"`
 		LowerLimit       string       `json:"LL"`
-		Volume           *float64     `json:"Vo"`
-		TurnoverValue    *float64     `json:"Va"`
+		Volume           *json.Number `json:"Vo"`
+		TurnoverValue    *json.Number `json:"Va"`
 		AdjustmentFactor json.Number  `json:"AdjFactor"`
 		AdjustedOpen     *json.Number `json:"AdjO"`
 		AdjustedHigh     *json.Number `json:"AdjH"`
 		AdjustedLow      *json.Number `json:"AdjL"`
 		AdjustedClose    *json.Number `json:"AdjC"`
-		AdjustedVolume   *float64     `json:"AdjVo"`
+		AdjustedVolume   *json.Number `json:"AdjVo"`
 	}
 	var volume, turnoverValue *int64
 	if err := json.Unmarshal(b, &raw); err != nil {
@@ -200,18 +265,15 @@ func (sp *StockPrice) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if raw.Volume != nil {
-		v := int64(*raw.Volume)
-		volume = &v
+	if volume, err = unmarshalInt64(raw.Volume); err != nil {
+		return err
 	}
-	if raw.TurnoverValue != nil {
-		v := int64(*raw.TurnoverValue)
-		turnoverValue = &v
+	if turnoverValue, err = unmarshalInt64(raw.TurnoverValue); err != nil {
+		return err
 	}
-	var adjustedVolume *int64
-	if raw.AdjustedVolume != nil {
-		v := int64(*raw.AdjustedVolume)
-		adjustedVolume = &v
+	adjustedVolume, err := unmarshalInt64(raw.AdjustedVolume)
+	if err != nil {
+		return err
 	}
 	sp.Date = raw.Date
 	sp.Code = raw.Code
@@ -232,6 +294,23 @@ func (sp *StockPrice) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// unmarshalInt64 converts a JSON number, which the API may send either bare or quoted, to an int64.
+// Fractional values are truncated. It returns nil if n is nil.
+func unmarshalInt64(n *json.Number) (*int64, error) {
+	if n == nil {
+		return nil, nil
+	}
+	if i, err := n.Int64(); err == nil {
+		return &i, nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalInt64: invalid number %q: %w", n.String(), err)
+	}
+	i := int64(f)
+	return &i, nil
+}
+
 func unmarshalLimit(s string) (bool, error) {
 	switch s {
 	case "0":
@@ -262,6 +341,9 @@ type stockPriceParameters struct {
 }
 
 func (p stockPriceParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.Date != nil {
 		v.Add("date", *p.Date)
@@ -269,7 +351,11 @@ func (p stockPriceParameters) values() (url.Values, error) {
 		if p.Code == nil {
 			return nil, errors.New("code or date is required")
 		}
-		v.Add("code", *p.Code)
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
 		if p.From != nil {
 			v.Add("from", *p.From)
 		}
@@ -283,68 +369,371 @@ func (p stockPriceParameters) values() (url.Values, error) {
 	return v, nil
 }
 
-type stockPriceResponse struct {
-	Data          []StockPrice `json:"data"`
-	PaginationKey *string      `json:"pagination_key"`
+var _ Request[StockPrice] = (*stockPriceParameters)(nil)
+
+func (p *stockPriceParameters) Path() string                 { return "/equities/bars/daily" }
+func (p *stockPriceParameters) Values() (url.Values, error)  { return p.values() }
+func (p *stockPriceParameters) SetPaginationKey(key *string) { p.PaginationKey = key }
+
+func (p *stockPriceParameters) Send(ctx context.Context, c *Client) ([]StockPrice, error) {
+	return Do[StockPrice](ctx, c, p)
+}
+
+// StockPrice retrieves daily stock prices from the /equities/bars/daily endpoint.
+// It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged; a range without From is walked
+// back from To one year at a time until a year without records, and a range without To ends today (JST).
+func (c *Client) StockPrice(ctx context.Context, req StockPriceRequest) ([]StockPrice, error) {
+	fetch := func(from, to *string) ([]StockPrice, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return Do[StockPrice](ctx, c, &stockPriceParameters{StockPriceRequest: chunk})
+	}
+	if req.Date != nil {
+		return fetch(req.From, req.To)
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
+}
+
+// StockPriceWithChannel retrieves daily stock prices and streams each record to the provided channel.
+// The channel is closed when all records have been sent or an error occurs.
+// Records are decoded and sent one at a time, so memory use does not grow with the page size.
+func (c *Client) StockPriceWithChannel(ctx context.Context, req StockPriceRequest, ch chan<- StockPrice) error {
+	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string, emit func(StockPrice) error) (streamedPage[StockPrice], error) {
+		params := stockPriceParameters{StockPriceRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/equities/bars/daily", params, emit)
+	})
+}
+
+// StockPriceSeq returns an iterator over daily stock prices, for use with range-over-func:
+//
+//	for price, err := range client.StockPriceSeq(ctx, req) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(price.Date, price.Close)
+//	}
+//
+// Records are decoded one at a time like [Client.StockPriceWithChannel], and breaking out of the loop stops
+// fetching. A failure is yielded once as the final pair with a zero StockPrice. Date ranges are not split into chunks.
+func (c *Client) StockPriceSeq(ctx context.Context, req StockPriceRequest) iter.Seq2[StockPrice, error] {
+	return fetchAllPagesSeq(ctx, c, func(ctx context.Context, paginationKey *string, emit func(StockPrice) error) (streamedPage[StockPrice], error) {
+		params := stockPriceParameters{StockPriceRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/equities/bars/daily", params, emit)
+	})
+}
+
+// StockPriceDebug retrieves daily stock prices like [Client.StockPrice] and also returns the JSON object each
+// record was decoded from, so raw[i] is the source of prices[i]. It is a diagnostic tool for field-mapping
+// problems (e.g., a field that is always zero because of a compact key mismatch), not for production use.
+// A record that fails to decode is kept as a zero StockPrice next to its raw JSON, and its error is returned
+// joined with the others. Date ranges are not split into chunks.
+func (c *Client) StockPriceDebug(ctx context.Context, req StockPriceRequest) ([]StockPrice, []json.RawMessage, error) {
+	raw := make([]json.RawMessage, 0)
+	var page []json.RawMessage
+	err := paginate(ctx, c, func(ctx context.Context, paginationKey *string) (streamedPage[json.RawMessage], error) {
+		page = page[:0]
+		params := stockPriceParameters{StockPriceRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/equities/bars/daily", params, func(record json.RawMessage) error {
+			page = append(page, record)
+			return nil
+		})
+	}, func(streamedPage[json.RawMessage]) {
+		raw = append(raw, page...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	prices := make([]StockPrice, len(raw))
+	var errs []error
+	for i, record := range raw {
+		if err := json.Unmarshal(record, &prices[i]); err != nil {
+			prices[i] = StockPrice{}
+			errs = append(errs, fmt.Errorf("record %d: %w", i, err))
+		}
+	}
+	return prices, raw, errors.Join(errs...)
+}
+
+// recentTradingDaysMargin is the number of extra trading days fetched by RecentStockPrices
+// so that the latest n rows are still covered when the most recent days have no data yet.
+const recentTradingDaysMargin = 5
+
+// RecentStockPrices retrieves the most recent n trading days of daily prices for code.
+// It uses the trading calendar to pick a From date slightly more than n trading days back,
+// then trims the result to the last n rows. Codes listed for fewer than n days return what exists.
+func (c *Client) RecentStockPrices(ctx context.Context, code string, n int) ([]StockPrice, error) {
+	if n <= 0 {
+		return nil, errors.New("n must be positive")
+	}
+	now := time.Now().In(jst)
+	// Roughly two calendar days per trading day leaves room for weekends and long holidays.
+	from := now.AddDate(0, 0, -2*(n+recentTradingDaysMargin)-14).Format(dateLayout)
+	to := now.Format(dateLayout)
+	calendar, err := c.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading calendar: %w", err)
+	}
+	tradingDays := make([]string, 0, len(calendar))
+	for _, day := range calendar {
+		if day.IsTradingDay() {
+			tradingDays = append(tradingDays, day.Date)
+		}
+	}
+	if len(tradingDays) == 0 {
+		return nil, errors.New("no trading days found in calendar")
+	}
+	slices.Sort(tradingDays)
+	start := tradingDays[max(0, len(tradingDays)-n-recentTradingDaysMargin)]
+	prices, err := c.StockPrice(ctx, StockPriceRequest{Code: &code, From: &start, To: &to})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(prices, func(a, b StockPrice) int { return cmp.Compare(a.Date, b.Date) })
+	return prices[max(0, len(prices)-n):], nil
+}
+
+// MissingTradingDays returns the trading days in [from, to] (YYYY-MM-DD) for which prices has no record,
+// according to the trading calendar. It helps detect incomplete backfills, e.g., after a loop timeout.
+// prices is expected to hold a single code.
+func MissingTradingDays(ctx context.Context, client *Client, prices []StockPrice, from, to string) ([]string, error) {
+	calendar, err := client.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading calendar: %w", err)
+	}
+	return missingTradingDays(calendar, prices), nil
+}
+
+func missingTradingDays(calendar []TradingCalendar, prices []StockPrice) []string {
+	present := make(map[string]struct{}, len(prices))
+	for _, p := range prices {
+		present[p.Date] = struct{}{}
+	}
+	missing := make([]string, 0)
+	for _, day := range calendar {
+		if !day.IsTradingDay() {
+			continue
+		}
+		if _, ok := present[day.Date]; !ok {
+			missing = append(missing, day.Date)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// EnrichedStockPrice is a StockPrice annotated with master data of the security.
+type EnrichedStockPrice struct {
+	StockPrice
+	// CompanyName is the company name in Japanese (empty if no master record matched).
+	CompanyName string
+	// Sector33Name is the name of the 33-sector classification (empty if no master record matched).
+	Sector33Name string
+	// MarketName is the name of the market section (empty if no master record matched).
+	MarketName string
+}
+
+// EnrichStockPrices joins prices with master data by code, using for each price the most recent master record
+// dated on or before the price date. Prices without a matching master record are kept with empty metadata.
+func EnrichStockPrices(prices []StockPrice, master []IssueInformation) []EnrichedStockPrice {
+	byCode := make(map[string][]IssueInformation)
+	for _, issue := range master {
+		byCode[issue.Code] = append(byCode[issue.Code], issue)
+	}
+	for _, issues := range byCode {
+		slices.SortFunc(issues, func(a, b IssueInformation) int { return cmp.Compare(a.Date, b.Date) })
+	}
+	enriched := make([]EnrichedStockPrice, 0, len(prices))
+	for _, p := range prices {
+		e := EnrichedStockPrice{StockPrice: p}
+		issues := byCode[p.Code]
+		// Index of the first master record dated after the price date.
+		i, _ := slices.BinarySearchFunc(issues, p.Date, func(issue IssueInformation, date string) int {
+			if issue.Date <= date {
+				return -1
+			}
+			return 1
+		})
+		if i > 0 {
+			issue := issues[i-1]
+			e.CompanyName = issue.CompanyName
+			e.Sector33Name = issue.Sector33Name
+			e.MarketName = issue.MarketName
+		}
+		enriched = append(enriched, e)
+	}
+	return enriched
+}
+
+// MorningSessionStockPrice represents morning session OHLCV data for a security,
+// available before the afternoon session opens.
+type MorningSessionStockPrice struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// Code is the security code (ticker symbol).
+	Code string
+	// MorningOpen is the morning session opening price (nil if no trading occurred).
+	MorningOpen *json.Number
+	// MorningHigh is the morning session highest price (nil if no trading occurred).
+	MorningHigh *json.Number
+	// MorningLow is the morning session lowest price (nil if no trading occurred).
+	MorningLow *json.Number
+	// MorningClose is the morning session closing price (nil if no trading occurred).
+	MorningClose *json.Number
+	// MorningUpperLimit indicates whether the stock hit the daily price limit up in the morning session.
+	MorningUpperLimit bool
+	// MorningLowerLimit indicates whether the stock hit the daily price limit down in the morning session.
+	MorningLowerLimit bool
+	// MorningVolume is the morning session trading volume in shares (nil if no trading occurred).
+	MorningVolume *int64
+	// MorningTurnoverValue is the morning session trading value in yen (nil if no trading occurred).
+	MorningTurnoverValue *int64
+}
+
+func (sp *MorningSessionStockPrice) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Date                 string       `json:"Date"`
+		Code                 string       `json:"Code"`
+		MorningOpen          *json.Number `json:"MO"`
+		MorningHigh          *json.Number `json:"MH"`
+		MorningLow           *json.Number `json:"ML"`
+		MorningClose         *json.Number `json:"MC"`
+		MorningUpperLimit    string       `json:"MUL"`
+		MorningLowerLimit    string       `json:"MLL"`
+		MorningVolume        *json.Number `json:"MVo"`
+		MorningTurnoverValue *json.Number `json:"MVa"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	upperLimit, err := unmarshalLimit(raw.MorningUpperLimit)
+	if err != nil {
+		return err
+	}
+	lowerLimit, err := unmarshalLimit(raw.MorningLowerLimit)
+	if err != nil {
+		return err
+	}
+	volume, err := unmarshalInt64(raw.MorningVolume)
+	if err != nil {
+		return err
+	}
+	turnoverValue, err := unmarshalInt64(raw.MorningTurnoverValue)
+	if err != nil {
+		return err
+	}
+	sp.Date = raw.Date
+	sp.Code = raw.Code
+	sp.MorningOpen = raw.MorningOpen
+	sp.MorningHigh = raw.MorningHigh
+	sp.MorningLow = raw.MorningLow
+	sp.MorningClose = raw.MorningClose
+	sp.MorningUpperLimit = upperLimit
+	sp.MorningLowerLimit = lowerLimit
+	sp.MorningVolume = volume
+	sp.MorningTurnoverValue = turnoverValue
+	return nil
+}
+
+// MorningSessionStockPriceRequest specifies filter parameters for the MorningSessionStockPrice API.
+// Either Code or Date must be provided.
+type MorningSessionStockPriceRequest struct {
+	// Code filters by security code. Required if Date is not specified.
+	Code *string
+	// Date filters by a specific date in YYYY-MM-DD format. If specified, Code is ignored.
+	Date *string
+}
+
+type morningSessionStockPriceParameters struct {
+	MorningSessionStockPriceRequest
+	PaginationKey *string
+}
+
+func (p morningSessionStockPriceParameters) values() (url.Values, error) {
+	if err := checkDate("date", p.Date); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	if p.Date != nil {
+		v.Add("date", *p.Date)
+	} else {
+		if p.Code == nil {
+			return nil, errors.New("code or date is required")
+		}
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type morningSessionStockPriceResponse struct {
+	Data          records[MorningSessionStockPrice] `json:"data"`
+	PaginationKey *string                           `json:"pagination_key"`
 }
 
-func (r stockPriceResponse) Items() []StockPrice    { return r.Data }
-func (r stockPriceResponse) NextPageKey() *string { return r.PaginationKey }
+func (r morningSessionStockPriceResponse) Items() []MorningSessionStockPrice { return r.Data.items }
+func (r morningSessionStockPriceResponse) NextPageKey() *string              { return r.PaginationKey }
+func (r morningSessionStockPriceResponse) malformedRecords() []RecordError   { return r.Data.errs }
 
-func (c *Client) sendStockPriceRequest(ctx context.Context, params stockPriceParameters) (stockPriceResponse, error) {
-	var r stockPriceResponse
-	resp, err := c.sendRequest(ctx, "/equities/bars/daily", params)
+func (c *Client) sendMorningSessionStockPriceRequest(ctx context.Context, params morningSessionStockPriceParameters) (morningSessionStockPriceResponse, error) {
+	var r morningSessionStockPriceResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/equities/bars/daily/am", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r, nil
 }
 
-// StockPrice retrieves daily stock prices from the /equities/bars/daily endpoint.
+// MorningSessionStockPrice retrieves morning session stock prices from the /equities/bars/daily/am endpoint.
 // It automatically handles pagination to fetch all matching records.
-func (c *Client) StockPrice(ctx context.Context, req StockPriceRequest) ([]StockPrice, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (stockPriceResponse, error) {
-		params := stockPriceParameters{StockPriceRequest: req, PaginationKey: paginationKey}
-		return c.sendStockPriceRequest(ctx, params)
+func (c *Client) MorningSessionStockPrice(ctx context.Context, req MorningSessionStockPriceRequest) ([]MorningSessionStockPrice, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (morningSessionStockPriceResponse, error) {
+		params := morningSessionStockPriceParameters{MorningSessionStockPriceRequest: req, PaginationKey: paginationKey}
+		return c.sendMorningSessionStockPriceRequest(ctx, params)
 	})
 }
 
-// StockPriceWithChannel retrieves daily stock prices and streams each record to the provided channel.
-// The channel is closed when all records have been sent or an error occurs.
-func (c *Client) StockPriceWithChannel(ctx context.Context, req StockPriceRequest, ch chan<- StockPrice) error {
-	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string) (stockPriceResponse, error) {
-		params := stockPriceParameters{StockPriceRequest: req, PaginationKey: paginationKey}
-		return c.sendStockPriceRequest(ctx, params)
+// MorningSessionStockPriceWithChannel retrieves morning session stock prices and streams each record to the
+// provided channel. The channel is closed when all records have been sent or an error occurs.
+func (c *Client) MorningSessionStockPriceWithChannel(ctx context.Context, req MorningSessionStockPriceRequest, ch chan<- MorningSessionStockPrice) error {
+	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string, emit func(MorningSessionStockPrice) error) (streamedPage[MorningSessionStockPrice], error) {
+		params := morningSessionStockPriceParameters{MorningSessionStockPriceRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/equities/bars/daily/am", params, emit)
 	})
 }
 
-// Morning Session Stock Prices not implemented
-
 // TradingBalance represents trading activity metrics for a specific investor type.
-// All values are in units of 1,000 shares.
+// All values are in units of 1,000 shares and kept as json.Number because the API may send fractions.
 type TradingBalance struct {
 	// Sales is the total sell volume.
-	Sales int64
+	Sales json.Number
 	// Purchases is the total buy volume.
-	Purchases int64
+	Purchases json.Number
 	// Total is the sum of sales and purchases.
-	Total int64
+	Total json.Number
 	// Balance is the net position (Purchases - Sales).
-	Balance int64
+	Balance json.Number
 }
 
-func newTradingBalance(sell, buy, total, balance float64) TradingBalance {
+func newTradingBalance(sell, buy, total, balance json.Number) TradingBalance {
 	return TradingBalance{
-		Sales:     int64(sell),
-		Purchases: int64(buy),
-		Total:     int64(total),
-		Balance:   int64(balance),
+		Sales:     sell,
+		Purchases: buy,
+		Total:     total,
+		Balance:   balance,
 	}
 }
 
@@ -389,62 +778,62 @@ type InvestorType struct {
 
 func (it *InvestorType) UnmarshalJSON(b []byte) error {
 	var raw struct {
-		PubDate     string  `json:"PubDate"`
-		StDate      string  `json:"StDate"`
-		EnDate      string  `json:"EnDate"`
-		Section     string  `json:"Section"`
-		PropSell    float64 `json:"PropSell"`
-		PropBuy     float64 `json:"PropBuy"`
-		PropTot     float64 `json:"PropTot"`
-		PropBal     float64 `json:"PropBal"`
-		BrkSell     float64 `json:"BrkSell"`
-		BrkBuy      float64 `json:"BrkBuy"`
-		BrkTot      float64 `json:"BrkTot"`
-		BrkBal      float64 `json:"BrkBal"`
-		TotSell     float64 `json:"TotSell"`
-		TotBuy      float64 `json:"TotBuy"`
-		TotTot      float64 `json:"TotTot"`
-		TotBal      float64 `json:"TotBal"`
-		IndSell     float64 `json:"IndSell"`
-		IndBuy      float64 `json:"IndBuy"`
-		IndTot      float64 `json:"IndTot"`
-		IndBal      float64 `json:"IndBal"`
-		FrgnSell    float64 `json:"FrgnSell"`
-		FrgnBuy     float64 `json:"FrgnBuy"`
-		FrgnTot     float64 `json:"FrgnTot"`
-		FrgnBal     float64 `json:"FrgnBal"`
-		SecCoSell   float64 `json:"SecCoSell"`
-		SecCoBuy    float64 `json:"SecCoBuy"`
-		SecCoTot    float64 `json:"SecCoTot"`
-		SecCoBal    float64 `json:"SecCoBal"`
-		InvTrSell   float64 `json:"InvTrSell"`
-		InvTrBuy    float64 `json:"InvTrBuy"`
-		InvTrTot    float64 `json:"InvTrTot"`
-		InvTrBal    float64 `json:"InvTrBal"`
-		BusCoSell   float64 `json:"BusCoSell"`
-		BusCoBuy    float64 `json:"BusCoBuy"`
-		BusCoTot    float64 `json:"BusCoTot"`
-		BusCoBal    float64 `json:"BusCoBal"`
-		OthCoSell   float64 `json:"OthCoSell"`
-		OthCoBuy    float64 `json:"OthCoBuy"`
-		OthCoTot    float64 `json:"OthCoTot"`
-		OthCoBal    float64 `json:"OthCoBal"`
-		InsCoSell   float64 `json:"InsCoSell"`
-		InsCoBuy    float64 `json:"InsCoBuy"`
-		InsCoTot    float64 `json:"InsCoTot"`
-		InsCoBal    float64 `json:"InsCoBal"`
-		BankSell    float64 `json:"BankSell"`
-		BankBuy     float64 `json:"BankBuy"`
-		BankTot     float64 `json:"BankTot"`
-		BankBal     float64 `json:"BankBal"`
-		TrstBnkSell float64 `json:"TrstBnkSell"`
-		TrstBnkBuy  float64 `json:"TrstBnkBuy"`
-		TrstBnkTot  float64 `json:"TrstBnkTot"`
-		TrstBnkBal  float64 `json:"TrstBnkBal"`
-		OthFinSell  float64 `json:"OthFinSell"`
-		OthFinBuy   float64 `json:"OthFinBuy"`
-		OthFinTot   float64 `json:"OthFinTot"`
-		OthFinBal   float64 `json:"OthFinBal"`
+		PubDate     string      `json:"PubDate"`
+		StDate      string      `json:"StDate"`
+		EnDate      string      `json:"EnDate"`
+		Section     string      `json:"Section"`
+		PropSell    json.Number `json:"PropSell"`
+		PropBuy     json.Number `json:"PropBuy"`
+		PropTot     json.Number `json:"PropTot"`
+		PropBal     json.Number `json:"PropBal"`
+		BrkSell     json.Number `json:"BrkSell"`
+		BrkBuy      json.Number `json:"BrkBuy"`
+		BrkTot      json.Number `json:"BrkTot"`
+		BrkBal      json.Number `json:"BrkBal"`
+		TotSell     json.Number `json:"TotSell"`
+		TotBuy      json.Number `json:"TotBuy"`
+		TotTot      json.Number `json:"TotTot"`
+		TotBal      json.Number `json:"TotBal"`
+		IndSell     json.Number `json:"IndSell"`
+		IndBuy      json.Number `json:"IndBuy"`
+		IndTot      json.Number `json:"IndTot"`
+		IndBal      json.Number `json:"IndBal"`
+		FrgnSell    json.Number `json:"FrgnSell"`
+		FrgnBuy     json.Number `json:"FrgnBuy"`
+		FrgnTot     json.Number `json:"FrgnTot"`
+		FrgnBal     json.Number `json:"FrgnBal"`
+		SecCoSell   json.Number `json:"SecCoSell"`
+		SecCoBuy    json.Number `json:"SecCoBuy"`
+		SecCoTot    json.Number `json:"SecCoTot"`
+		SecCoBal    json.Number `json:"SecCoBal"`
+		InvTrSell   json.Number `json:"InvTrSell"`
+		InvTrBuy    json.Number `json:"InvTrBuy"`
+		InvTrTot    json.Number `json:"InvTrTot"`
+		InvTrBal    json.Number `json:"InvTrBal"`
+		BusCoSell   json.Number `json:"BusCoSell"`
+		BusCoBuy    json.Number `json:"BusCoBuy"`
+		BusCoTot    json.Number `json:"BusCoTot"`
+		BusCoBal    json.Number `json:"BusCoBal"`
+		OthCoSell   json.Number `json:"OthCoSell"`
+		OthCoBuy    json.Number `json:"OthCoBuy"`
+		OthCoTot    json.Number `json:"OthCoTot"`
+		OthCoBal    json.Number `json:"OthCoBal"`
+		InsCoSell   json.Number `json:"InsCoSell"`
+		InsCoBuy    json.Number `json:"InsCoBuy"`
+		InsCoTot    json.Number `json:"InsCoTot"`
+		InsCoBal    json.Number `json:"InsCoBal"`
+		BankSell    json.Number `json:"BankSell"`
+		BankBuy     json.Number `json:"BankBuy"`
+		BankTot     json.Number `json:"BankTot"`
+		BankBal     json.Number `json:"BankBal"`
+		TrstBnkSell json.Number `json:"TrstBnkSell"`
+		TrstBnkBuy  json.Number `json:"TrstBnkBuy"`
+		TrstBnkTot  json.Number `json:"TrstBnkTot"`
+		TrstBnkBal  json.Number `json:"TrstBnkBal"`
+		OthFinSell  json.Number `json:"OthFinSell"`
+		OthFinBuy   json.Number `json:"OthFinBuy"`
+		OthFinTot   json.Number `json:"OthFinTot"`
+		OthFinBal   json.Number `json:"OthFinBal"`
 	}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
@@ -485,6 +874,9 @@ type investorTypeParameters struct {
 }
 
 func (p investorTypeParameters) values() (url.Values, error) {
+	if err := checkDateRange(nil, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.Section != nil {
 		v.Add("section", *p.Section)
@@ -502,23 +894,25 @@ func (p investorTypeParameters) values() (url.Values, error) {
 }
 
 type investorTypeResponse struct {
-	Data          []InvestorType `json:"data"`
-	PaginationKey *string        `json:"pagination_key"`
+	Data          records[InvestorType] `json:"data"`
+	PaginationKey *string               `json:"pagination_key"`
 }
 
-func (r investorTypeResponse) Items() []InvestorType  { return r.Data }
-func (r investorTypeResponse) NextPageKey() *string { return r.PaginationKey }
+func (r investorTypeResponse) Items() []InvestorType           { return r.Data.items }
+func (r investorTypeResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r investorTypeResponse) malformedRecords() []RecordError { return r.Data.errs }
 
 func (c *Client) sendInvestorTypeRequest(ctx context.Context, params investorTypeParameters) (investorTypeResponse, error) {
 	var r investorTypeResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/equities/investor-types", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r, nil
@@ -526,10 +920,91 @@ func (c *Client) sendInvestorTypeRequest(ctx context.Context, params investorTyp
 
 // InvestorType retrieves weekly trading data by investor type from the /equities/investor-types endpoint.
 // It automatically handles pagination to fetch all matching records.
+// If the range reaches outside the weeks the subscription plan covers, the covered part is fetched instead
+// of failing with a 403, and a [WarningTruncatedRange] is reported to the warning handler.
 // See https://jpx-jquants.com/en/spec/eq-investor-types for API details.
 func (c *Client) InvestorType(ctx context.Context, req InvestorTypeRequest) ([]InvestorType, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (investorTypeResponse, error) {
+	fetch := func(req InvestorTypeRequest) ([]InvestorType, error) {
+		return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (investorTypeResponse, error) {
+			params := investorTypeParameters{InvestorTypeRequest: req, PaginationKey: paginationKey}
+			return c.sendInvestorTypeRequest(ctx, params)
+		})
+	}
+	data, err := fetch(req)
+	var forbidden Forbidden
+	if !errors.As(err, &forbidden) {
+		return data, err
+	}
+	from, to, ok := clipToCoveredRange(req.From, req.To, forbidden)
+	if !ok || (req.From != nil && *req.From == from && req.To != nil && *req.To == to) {
+		return data, err
+	}
+	c.report(Warning{
+		Code:    WarningTruncatedRange,
+		Message: fmt.Sprintf("investor type range truncated to %s ~ %s covered by the subscription plan", from, to),
+		Context: map[string]string{"from": from, "to": to},
+	})
+	clipped := req
+	clipped.From, clipped.To = &from, &to
+	return fetch(clipped)
+}
+
+// InvestorTypeSeq returns an iterator over weekly investor type records, decoded one at a time like
+// [Client.StockPriceSeq]. Unlike [Client.InvestorType], a range outside the plan's coverage is not truncated;
+// the 403 is yielded as the error.
+func (c *Client) InvestorTypeSeq(ctx context.Context, req InvestorTypeRequest) iter.Seq2[InvestorType, error] {
+	return fetchAllPagesSeq(ctx, c, func(ctx context.Context, paginationKey *string, emit func(InvestorType) error) (streamedPage[InvestorType], error) {
+		params := investorTypeParameters{InvestorTypeRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/equities/investor-types", params, emit)
+	})
+}
+
+// clipToCoveredRange intersects the requested range (nil bounds are open) with the range covered by the plan,
+// as stated in a plan restriction error. ok is false if the error states no range or the ranges do not overlap.
+func clipToCoveredRange(from, to *string, forbidden Forbidden) (string, string, bool) {
+	coveredFrom, coveredTo, ok := forbidden.CoveredRange()
+	if !ok {
+		return "", "", false
+	}
+	if from != nil && *from > coveredFrom {
+		coveredFrom = *from
+	}
+	if to != nil && *to < coveredTo {
+		coveredTo = *to
+	}
+	if coveredFrom > coveredTo {
+		return "", "", false
+	}
+	return coveredFrom, coveredTo, true
+}
+
+// investorTypeFullLoopTimeout is the minimum pagination loop timeout used by InvestorTypeFull.
+const investorTypeFullLoopTimeout = 5 * time.Minute
+
+// InvestorTypeFull retrieves the complete investor type history for section (e.g., "TSEPrime"),
+// from the earliest available week to the latest. An empty section fetches all sections.
+// onProgress, if not nil, is called after each page with the number of weekly records fetched so far.
+// The pagination loop timeout is raised to at least investorTypeFullLoopTimeout for this call.
+// The result is sorted by EndDate ascending.
+func (c *Client) InvestorTypeFull(ctx context.Context, section string, onProgress func(weeks int)) ([]InvestorType, error) {
+	var req InvestorTypeRequest
+	if section != "" {
+		req.Section = &section
+	}
+	ctx = withLoopTimeout(ctx, max(c.loopTimeout, investorTypeFullLoopTimeout))
+	data := make([]InvestorType, 0)
+	err := paginate(ctx, c, func(ctx context.Context, paginationKey *string) (investorTypeResponse, error) {
 		params := investorTypeParameters{InvestorTypeRequest: req, PaginationKey: paginationKey}
 		return c.sendInvestorTypeRequest(ctx, params)
+	}, func(resp investorTypeResponse) {
+		data = append(data, resp.Items()...)
+		if onProgress != nil {
+			onProgress(len(data))
+		}
 	})
+	if err != nil && !errors.As(err, &MalformedRecordsError{}) {
+		return nil, err
+	}
+	slices.SortStableFunc(data, func(a, b InvestorType) int { return cmp.Compare(a.EndDate, b.EndDate) })
+	return data, err
 }
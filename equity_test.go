@@ -2,6 +2,11 @@ package jquants
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/s-shiga/jquants-go/v2/codes"
@@ -9,13 +14,28 @@ import (
 
 func TestClient_IssueInformation(t *testing.T) {
 	client := setupClient(t)
-	resp, err := client.IssueInformation(t.Context(), IssueInformationRequest{})
+	resp, err := client.IssueInformation(t.Context(), IssueInformationRequest{AllData: true})
 	if err != nil {
 		t.Errorf("Failed to get issue information: %v", err)
 	}
 	if len(resp) == 0 {
 		t.Error("Empty response")
 	}
+	// The TSE lists roughly 4,000 securities; far fewer rows means the master was truncated.
+	if len(resp) < 3000 {
+		t.Errorf("Master looks truncated: %d records", len(resp))
+	}
+}
+
+func TestClient_ActiveCodes(t *testing.T) {
+	client := setupClient(t)
+	codes, err := client.ActiveCodes(t.Context(), "2025-01-06")
+	if err != nil {
+		t.Errorf("Failed to get active codes: %v", err)
+	}
+	if len(codes) == 0 {
+		t.Error("Empty active codes")
+	}
 }
 
 func TestClient_StockPrice(t *testing.T) {
@@ -31,6 +51,18 @@ func TestClient_StockPrice(t *testing.T) {
 	}
 }
 
+func TestClient_StockPriceDebug(t *testing.T) {
+	var code = "13010"
+	client := setupClient(t)
+	prices, raw, err := client.StockPriceDebug(t.Context(), StockPriceRequest{Code: &code})
+	if err != nil {
+		t.Fatalf("Failed to get stock price: %s", err)
+	}
+	if len(prices) == 0 || len(prices) != len(raw) {
+		t.Errorf("Unexpected result length: %d prices, %d raw records", len(prices), len(raw))
+	}
+}
+
 func TestClient_StockPriceWithChannel(t *testing.T) {
 	var code = "13010"
 	client := setupClient(t)
@@ -52,6 +84,42 @@ func TestClient_StockPriceWithChannel(t *testing.T) {
 	}
 }
 
+func TestClient_MorningSessionStockPrice(t *testing.T) {
+	var code = "13010"
+	client := setupClient(t)
+	res, err := client.MorningSessionStockPrice(t.Context(), MorningSessionStockPriceRequest{Code: &code})
+	if err != nil {
+		t.Errorf("Failed to get morning session stock price: %s", err)
+	}
+	if len(res) == 0 {
+		t.Error("Empty morning session stock price")
+	}
+}
+
+func TestClient_RecentStockPrices(t *testing.T) {
+	client := setupClient(t)
+	res, err := client.RecentStockPrices(t.Context(), "13010", 30)
+	if err != nil {
+		t.Errorf("Failed to get recent stock prices: %s", err)
+	}
+	if len(res) == 0 || len(res) > 30 {
+		t.Errorf("Unexpected number of recent stock prices: %d", len(res))
+	}
+}
+
+func TestClient_StockPrices(t *testing.T) {
+	from, to := "2025-01-06", "2025-01-10"
+	client := setupClient(t)
+	req := StockPriceRequest{From: &from, To: &to}
+	res, err := client.StockPrices(t.Context(), []string{"13010", "72030"}, req)
+	if err != nil {
+		t.Errorf("Failed to get stock prices: %s", err)
+	}
+	if len(res) != 2 {
+		t.Errorf("Unexpected number of codes: %d", len(res))
+	}
+}
+
 func TestClient_InvestorType(t *testing.T) {
 	var code = codes.SectionPrime
 	client := setupClient(t)
@@ -64,3 +132,252 @@ func TestClient_InvestorType(t *testing.T) {
 		t.Error("Empty investor type")
 	}
 }
+
+func TestClient_InvestorTypeFull(t *testing.T) {
+	client := setupClient(t)
+	weeks := 0
+	res, err := client.InvestorTypeFull(t.Context(), codes.SectionPrime, func(n int) { weeks = n })
+	if err != nil {
+		t.Errorf("Failed to get investor type history: %s", err)
+	}
+	if len(res) == 0 || weeks != len(res) {
+		t.Errorf("Unexpected investor type history: %d records, %d reported", len(res), weeks)
+	}
+}
+
+func TestStockPrice_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"number", `{"Date":"2025-01-06","Code":"13010","UL":"0","LL":"0","Vo":1200.0,"Va":3456789.0,"AdjFactor":1.0,"AdjVo":1200.0}`},
+		{"string", `{"Date":"2025-01-06","Code":"13010","UL":"0","LL":"0","Vo":"1200","Va":"3456789","AdjFactor":1.0,"AdjVo":"1200"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sp StockPrice
+			if err := json.Unmarshal([]byte(tt.data), &sp); err != nil {
+				t.Fatalf("Failed to unmarshal stock price: %v", err)
+			}
+			if sp.Volume == nil || *sp.Volume != 1200 {
+				t.Errorf("Unexpected volume: %v", sp.Volume)
+			}
+			if sp.TurnoverValue == nil || *sp.TurnoverValue != 3456789 {
+				t.Errorf("Unexpected turnover value: %v", sp.TurnoverValue)
+			}
+			if sp.AdjustedVolume == nil || *sp.AdjustedVolume != 1200 {
+				t.Errorf("Unexpected adjusted volume: %v", sp.AdjustedVolume)
+			}
+		})
+	}
+}
+
+func TestMorningSessionStockPrice_UnmarshalJSON(t *testing.T) {
+	var sp MorningSessionStockPrice
+	data := `{"Date":"2025-01-06","Code":"13010","MO":"4000","MC":4050.0,"MUL":"1","MLL":"0","MVo":"1200","MVa":4860000.0}`
+	if err := json.Unmarshal([]byte(data), &sp); err != nil {
+		t.Fatalf("Failed to unmarshal morning session stock price: %v", err)
+	}
+	if sp.MorningClose == nil || *sp.MorningClose != "4050.0" || sp.MorningHigh != nil {
+		t.Errorf("Unexpected prices: close=%v, high=%v", sp.MorningClose, sp.MorningHigh)
+	}
+	if !sp.MorningUpperLimit || sp.MorningLowerLimit {
+		t.Errorf("Unexpected limits: upper=%v, lower=%v", sp.MorningUpperLimit, sp.MorningLowerLimit)
+	}
+	if sp.MorningVolume == nil || *sp.MorningVolume != 1200 || sp.MorningTurnoverValue == nil || *sp.MorningTurnoverValue != 4860000 {
+		t.Errorf("Unexpected volume fields: volume=%v, turnover=%v", sp.MorningVolume, sp.MorningTurnoverValue)
+	}
+	if err := json.Unmarshal([]byte(`{"MUL":"2","MLL":"0"}`), &sp); err == nil {
+		t.Error("Expected error for an unknown limit flag")
+	}
+	if _, err := (morningSessionStockPriceParameters{}).values(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+}
+
+func TestMissingTradingDays(t *testing.T) {
+	calendar := []TradingCalendar{
+		{Date: "2025-01-03", DayType: 0},
+		{Date: "2025-01-06", DayType: 1},
+		{Date: "2025-01-07", DayType: 1},
+		{Date: "2025-01-08", DayType: 1},
+	}
+	prices := []StockPrice{{Date: "2025-01-06"}, {Date: "2025-01-08"}}
+	missing := missingTradingDays(calendar, prices)
+	if len(missing) != 1 || missing[0] != "2025-01-07" {
+		t.Errorf("Unexpected missing trading days: %v", missing)
+	}
+}
+
+func TestEnrichStockPrices(t *testing.T) {
+	master := []IssueInformation{
+		{Date: "2025-01-10", Code: "13010", CompanyName: "New", Sector33Name: "Foods", MarketName: "Prime"},
+		{Date: "2025-01-01", Code: "13010", CompanyName: "Old", Sector33Name: "Foods", MarketName: "Standard"},
+	}
+	prices := []StockPrice{
+		{Date: "2024-12-30", Code: "13010"},
+		{Date: "2025-01-06", Code: "13010"},
+		{Date: "2025-01-10", Code: "13010"},
+		{Date: "2025-01-10", Code: "99990"},
+	}
+	enriched := EnrichStockPrices(prices, master)
+	if len(enriched) != len(prices) {
+		t.Fatalf("Unexpected number of enriched prices: %d", len(enriched))
+	}
+	for i, want := range []string{"", "Old", "New", ""} {
+		if enriched[i].CompanyName != want {
+			t.Errorf("Unexpected company name for %s %s: got %q, want %q", enriched[i].Code, enriched[i].Date, enriched[i].CompanyName, want)
+		}
+	}
+	if enriched[1].MarketName != "Standard" {
+		t.Errorf("Unexpected market name: %q", enriched[1].MarketName)
+	}
+}
+
+func TestIssueInformation_UnmarshalJSON_MarginCode(t *testing.T) {
+	two := int8(2)
+	tests := []struct {
+		name   string
+		margin string
+		want   *int8
+	}{
+		{"numeric", `"2"`, &two},
+		{"empty", `""`, nil},
+		{"null", `null`, nil},
+		{"garbage", `"-"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `{"Date":"2025-01-06","Code":"13010","S17":"1","Mrgn":` + tt.margin + `}`
+			var ii IssueInformation
+			if err := json.Unmarshal([]byte(data), &ii); err != nil {
+				t.Fatalf("Failed to unmarshal issue information: %v", err)
+			}
+			switch {
+			case tt.want == nil && ii.MarginCode != nil:
+				t.Errorf("Expected nil margin code, got %d", *ii.MarginCode)
+			case tt.want != nil && (ii.MarginCode == nil || *ii.MarginCode != *tt.want):
+				t.Errorf("Unexpected margin code: got %v, want %d", ii.MarginCode, *tt.want)
+			}
+		})
+	}
+}
+
+func TestInvestorType_UnmarshalJSON(t *testing.T) {
+	data := `{"PubDate":"2024-01-11","StDate":"2024-01-04","EnDate":"2024-01-05","Section":"TSEPrime","PropSell":123456.5,"PropBuy":100000,"PropTot":223456.5,"PropBal":-23456.5}`
+	var it InvestorType
+	if err := json.Unmarshal([]byte(data), &it); err != nil {
+		t.Fatalf("Failed to unmarshal investor type: %v", err)
+	}
+	if it.Proprietary.Sales != "123456.5" || it.Proprietary.Balance != "-23456.5" {
+		t.Errorf("Fraction was not preserved: %+v", it.Proprietary)
+	}
+	if it.Proprietary.Purchases != "100000" {
+		t.Errorf("Unexpected purchases: %s", it.Proprietary.Purchases)
+	}
+}
+
+func TestIssueInformationParameters_AllData(t *testing.T) {
+	if _, err := (issueInformationParameters{}).values(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+	v, err := issueInformationParameters{IssueInformationRequest: IssueInformationRequest{AllData: true}}.values()
+	if err != nil || len(v) != 0 {
+		t.Errorf("Unexpected values for AllData request: %v, %v", v, err)
+	}
+}
+
+func TestStockPriceParameters_CodeNormalization(t *testing.T) {
+	for _, code := range []string{"1301", "13010", " 1301 ", "130a"} {
+		v, err := stockPriceParameters{StockPriceRequest: StockPriceRequest{Code: &code}}.values()
+		if err != nil {
+			t.Errorf("Unexpected error for code %q: %v", code, err)
+			continue
+		}
+		want := "13010"
+		if code == "130a" {
+			want = "130A0"
+		}
+		if got := v.Get("code"); got != want {
+			t.Errorf("Unexpected code for %q: got %s, want %s", code, got, want)
+		}
+	}
+	for _, code := range []string{"130", "130100", "13-0", ""} {
+		if _, err := codes.NormalizeCode(code); err == nil {
+			t.Errorf("Expected error for code %q", code)
+		}
+		if _, err := (stockPriceParameters{StockPriceRequest: StockPriceRequest{Code: &code}}).values(); err == nil {
+			t.Errorf("Expected stock price request with code %q to be rejected", code)
+		}
+	}
+}
+
+func TestInvestorType_TruncatedRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("from") < "2023-01-09" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Your subscription covers the following dates: 2023-01-09 ~ 2025-01-09. If you want more data, please check other plans."}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"PubDate":"2023-01-19","StDate":"2023-01-10","EnDate":"2023-01-13","Section":"TSEPrime"}]}`)
+	}))
+	defer server.Close()
+	var warnings []Warning
+	client := NewClient(server.URL, "test", WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	from, to := "2020-01-01", "2023-06-30"
+	data, err := client.InvestorType(t.Context(), InvestorTypeRequest{From: &from, To: &to})
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Expected the covered part of the range, got %v, %v", data, err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != WarningTruncatedRange || warnings[0].Context["from"] != "2023-01-09" || warnings[0].Context["to"] != to {
+		t.Errorf("Unexpected warnings: %+v", warnings)
+	}
+}
+
+func TestStockPriceSeq(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("pagination_key") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"Date":"2025-01-06","Code":"13010","UL":"0","LL":"0"},{"Date":"2025-01-07","Code":"13010","UL":"0","LL":"0"}],"pagination_key":"next"}`)
+		default:
+			if requests == 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"message":"internal server error"}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"Date":"2025-01-08","Code":"13010","UL":"0","LL":"0"}]}`)
+		}
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil)
+	code := "13010"
+
+	var dates []string
+	for p, err := range client.StockPriceSeq(t.Context(), StockPriceRequest{Code: &code}) {
+		if err != nil {
+			t.Fatalf("Failed to iterate stock prices: %v", err)
+		}
+		dates = append(dates, p.Date)
+	}
+	if !slices.Equal(dates, []string{"2025-01-06", "2025-01-07", "2025-01-08"}) || requests != 3 {
+		t.Errorf("Unexpected iteration: dates=%v, requests=%d", dates, requests)
+	}
+
+	requests = 0
+	for range client.StockPriceSeq(t.Context(), StockPriceRequest{Code: &code}) {
+		break
+	}
+	if requests != 1 {
+		t.Errorf("Expected no further pages after break, got %d requests", requests)
+	}
+
+	var last error
+	for _, err := range client.StockPriceSeq(t.Context(), StockPriceRequest{}) {
+		last = err
+	}
+	if last == nil {
+		t.Error("Expected the error to be yielded")
+	}
+}
@@ -0,0 +1,162 @@
+package jquants
+
+import (
+	"fmt"
+	"math"
+)
+
+// The figures below are the assumptions behind EstimateRequests. They are rough sizes of the J-Quants
+// datasets, not limits enforced by the API, so treat the estimates as orders of magnitude.
+const (
+	// estimatedRecordsPerPage is the number of records assumed to fit in one response page.
+	estimatedRecordsPerPage = 5000
+	// estimatedListedIssues is the number of issues in a market-wide equity snapshot for one date.
+	estimatedListedIssues = 4400
+	// estimatedOptionContracts is the number of Nikkei 225 option contracts in a daily snapshot.
+	estimatedOptionContracts = 3000
+	// estimatedFuturesContracts is the number of futures contracts in a daily snapshot of all categories.
+	estimatedFuturesContracts = 500
+	// estimatedIndices is the number of indices in a market-wide index snapshot for one date.
+	estimatedIndices = 80
+	// estimatedSectors is the number of 33-sector rows in a short selling snapshot for one date.
+	estimatedSectors = 33
+	// estimatedSections is the number of market sections in the investor type data.
+	estimatedSections = 5
+	// tradingDaysPerYear is the approximate number of trading days in the 365-day chunks ranges are split into.
+	tradingDaysPerYear = 245
+)
+
+// EstimateRequests estimates how many HTTP requests a query will make, so a backfill can be checked against
+// the plan's per-minute quota before it starts. req is one of the endpoint request types (e.g., a
+// [StockPriceRequest]) and tradingDays is the number of trading days its range covers. Requests with a Date
+// are single-day snapshots and ignore tradingDays.
+//
+// The estimate assumes about 5,000 records per page, about 4,400 listed issues, 3,000 option contracts,
+// 500 futures contracts, 80 indices and 33 sectors per market-wide snapshot, one record per trading day for a
+// single code, one record per week for margin balances and investor types, and 245 trading days per 365-day
+// chunk for endpoints whose ranges are split. Date snapshots of statements, dividends, morning session prices,
+// and short selling positions are assumed to hold at most one record per listed issue, and the statement and
+// dividend history of a single code fits in one page. Retries are not counted. For batch helpers such as
+// [Client.StockPrices], multiply by the number of codes. It returns an error for an unsupported request type.
+func EstimateRequests(req any, tradingDays int) (int, error) {
+	tradingDays = max(tradingDays, 1)
+	var perDay float64
+	chunked := true
+	switch r := req.(type) {
+	case IssueInformationRequest:
+		if r.Code != nil {
+			return 1, nil
+		}
+		return pages(estimatedListedIssues), nil
+	case StockPriceRequest:
+		if r.Date != nil {
+			return pages(estimatedListedIssues), nil
+		}
+		perDay = 1
+	case InvestorTypeRequest:
+		perDay, chunked = 1.0/5, false
+		if r.Section == nil {
+			perDay *= estimatedSections
+		}
+	case MarginTradingOutstandingRequest:
+		if r.Date != nil && r.Code == nil {
+			return pages(estimatedListedIssues), nil
+		}
+		perDay = 1.0 / 5
+	case ShortSellingValueRequest:
+		if r.Date != nil {
+			return pages(estimatedSectors), nil
+		}
+		perDay = 1
+	case TradingCalendarRequest:
+		return 1, nil
+	case IndexPriceRequest:
+		if r.Date != nil {
+			return pages(estimatedIndices), nil
+		}
+		perDay = 1
+	case TopixPriceRequest:
+		perDay = 1
+	case IndexOptionPriceRequest:
+		return pages(estimatedOptionContracts), nil
+	case FuturesPriceRequest:
+		return pages(estimatedFuturesContracts), nil
+	case MorningSessionStockPriceRequest:
+		if r.Date != nil {
+			return pages(estimatedListedIssues), nil
+		}
+		return 1, nil
+	case BreakdownRequest:
+		if r.Date != nil {
+			return pages(estimatedListedIssues), nil
+		}
+		perDay = 1
+	case ShortSellingPositionsRequest:
+		if r.DisclosedDate != nil || r.CalculatedDate != nil {
+			return pages(estimatedListedIssues), nil
+		}
+		perDay, chunked = 1, false
+	case FinancialStatementsRequest:
+		if r.Code == nil {
+			return pages(estimatedListedIssues), nil
+		}
+		return 1, nil
+	case DividendRequest:
+		if r.Date != nil {
+			return pages(estimatedListedIssues), nil
+		}
+		return 1, nil
+	case EarningsAnnouncementRequest:
+		return pages(estimatedListedIssues), nil
+	default:
+		return 0, fmt.Errorf("unsupported request type %T", req)
+	}
+	if !chunked {
+		return pages(perDay * float64(tradingDays)), nil
+	}
+	requests := 0
+	for remaining := tradingDays; remaining > 0; remaining -= tradingDaysPerYear {
+		requests += pages(perDay * float64(min(remaining, tradingDaysPerYear)))
+	}
+	return requests, nil
+}
+
+// EstimateRequests is the calendar-aware variant of [EstimateRequests]: it counts the trading days between
+// req's From and To in the calendar instead of taking them as an argument. A missing bound defaults to the
+// first or last date of the calendar.
+func (c *Calendar) EstimateRequests(req any) (int, error) {
+	var from, to *string
+	switch r := req.(type) {
+	case StockPriceRequest:
+		from, to = r.From, r.To
+	case InvestorTypeRequest:
+		from, to = r.From, r.To
+	case MarginTradingOutstandingRequest:
+		from, to = r.From, r.To
+	case ShortSellingValueRequest:
+		from, to = r.From, r.To
+	case TradingCalendarRequest:
+		from, to = r.From, r.To
+	case IndexPriceRequest:
+		from, to = r.From, r.To
+	case TopixPriceRequest:
+		from, to = r.From, r.To
+	case BreakdownRequest:
+		from, to = r.From, r.To
+	case DividendRequest:
+		from, to = r.From, r.To
+	}
+	first, last := "", "9999-12-31"
+	if from != nil {
+		first = *from
+	}
+	if to != nil {
+		last = *to
+	}
+	return EstimateRequests(req, len(c.TradingDays(first, last)))
+}
+
+// pages returns the number of pages needed for records, which is at least one.
+func pages(records float64) int {
+	return max(1, int(math.Ceil(records/estimatedRecordsPerPage)))
+}
@@ -0,0 +1,59 @@
+package jquants
+
+import "testing"
+
+func TestEstimateRequests(t *testing.T) {
+	code, date, sector := "13010", "2024-01-04", "0050"
+	tests := []struct {
+		name        string
+		req         any
+		tradingDays int
+		want        int
+	}{
+		{"single code year", StockPriceRequest{Code: &code}, 245, 1},
+		{"single code five years", StockPriceRequest{Code: &code}, 1225, 5},
+		{"market snapshot", StockPriceRequest{Date: &date}, 1000, 1},
+		{"all sectors on a date", ShortSellingValueRequest{Date: &date}, 245, 1},
+		{"one sector", ShortSellingValueRequest{Sector33Code: &sector}, 490, 2},
+		{"margin snapshot", MarginTradingOutstandingRequest{Date: &date}, 0, 1},
+		{"statements of a code", FinancialStatementsRequest{Code: &code}, 4000, 1},
+		{"statements on a date", FinancialStatementsRequest{Date: &date}, 0, 1},
+		{"dividends of a code", DividendRequest{Code: &code}, 4000, 1},
+		{"dividends on a date", DividendRequest{Date: &date}, 0, 1},
+		{"futures snapshot", FuturesPriceRequest{Date: date}, 245, 1},
+		{"breakdown of a code", BreakdownRequest{Code: &code}, 735, 3},
+		{"breakdown snapshot", BreakdownRequest{Date: &date}, 245, 1},
+		{"morning session snapshot", MorningSessionStockPriceRequest{Date: &date}, 245, 1},
+		{"morning session of a code", MorningSessionStockPriceRequest{Code: &code}, 245, 1},
+		{"short positions of a code", ShortSellingPositionsRequest{Code: &code}, 12000, 3},
+		{"short positions on a date", ShortSellingPositionsRequest{DisclosedDate: &date}, 245, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateRequests(tt.req, tt.tradingDays)
+			if err != nil || got != tt.want {
+				t.Errorf("EstimateRequests() = %d, %v; want %d", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := EstimateRequests(code, 1); err == nil {
+		t.Error("Expected error for unsupported request type")
+	}
+
+	calendar := NewCalendar([]TradingCalendar{{Date: "2024-01-04", DayType: 1}, {Date: "2024-01-05", DayType: 1}, {Date: "2024-01-06", DayType: 0}})
+	if got, err := calendar.EstimateRequests(ShortSellingValueRequest{Sector33Code: &sector}); err != nil || got != 1 {
+		t.Errorf("Calendar.EstimateRequests() = %d, %v; want 1", got, err)
+	}
+	for _, req := range []any{
+		FinancialStatementsRequest{Code: &code},
+		DividendRequest{Code: &code, From: &date, To: &date},
+		FuturesPriceRequest{Date: date},
+		BreakdownRequest{Code: &code, From: &date},
+		MorningSessionStockPriceRequest{Code: &code},
+		ShortSellingPositionsRequest{Code: &code},
+	} {
+		if got, err := calendar.EstimateRequests(req); err != nil || got != 1 {
+			t.Errorf("Calendar.EstimateRequests(%T) = %d, %v; want 1", req, got, err)
+		}
+	}
+}
@@ -0,0 +1,624 @@
+package jquants
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+
+	"github.com/s-shiga/jquants-go/v2/codes"
+)
+
+// FinancialStatement represents a quarterly or annual earnings summary (kessan tanshin) of a listed company.
+// Numeric fields are nil when the disclosure leaves them blank, which the API sends as an empty string.
+type FinancialStatement struct {
+	// DisclosedDate is the disclosure date in YYYY-MM-DD format.
+	DisclosedDate string
+	// DisclosedTime is the disclosure time in HH:MM:SS format.
+	DisclosedTime string
+	// Code is the security code (ticker symbol).
+	Code string
+	// DisclosureNumber is the unique disclosure number, which increases with the disclosure time.
+	DisclosureNumber string
+	// TypeOfDocument is the document type (e.g., "FYFinancialStatements_Consolidated_JP").
+	TypeOfDocument string
+	// TypeOfCurrentPeriod is the reporting period type ("1Q", "2Q", "3Q", "4Q", "5Q", or "FY").
+	TypeOfCurrentPeriod string
+	// CurrentPeriodStartDate is the start of the reporting period in YYYY-MM-DD format.
+	CurrentPeriodStartDate string
+	// CurrentPeriodEndDate is the end of the reporting period in YYYY-MM-DD format.
+	CurrentPeriodEndDate string
+	// CurrentFiscalYearStartDate is the start of the fiscal year in YYYY-MM-DD format.
+	CurrentFiscalYearStartDate string
+	// CurrentFiscalYearEndDate is the end of the fiscal year in YYYY-MM-DD format.
+	CurrentFiscalYearEndDate string
+	// NetSales is the net sales in yen.
+	NetSales *json.Number
+	// OperatingProfit is the operating profit in yen.
+	OperatingProfit *json.Number
+	// OrdinaryProfit is the ordinary profit in yen.
+	OrdinaryProfit *json.Number
+	// Profit is the profit attributable to owners of the parent in yen.
+	Profit *json.Number
+	// EarningsPerShare is the earnings per share in yen.
+	EarningsPerShare *json.Number
+	// DilutedEarningsPerShare is the diluted earnings per share in yen.
+	DilutedEarningsPerShare *json.Number
+	// TotalAssets is the total assets in yen.
+	TotalAssets *json.Number
+	// Equity is the net assets in yen.
+	Equity *json.Number
+	// EquityToAssetRatio is the equity ratio (e.g., 0.45 for 45%).
+	EquityToAssetRatio *json.Number
+	// BookValuePerShare is the book value per share in yen.
+	BookValuePerShare *json.Number
+	// CashFlowsFromOperatingActivities is the operating cash flow in yen.
+	CashFlowsFromOperatingActivities *json.Number
+	// CashFlowsFromInvestingActivities is the investing cash flow in yen.
+	CashFlowsFromInvestingActivities *json.Number
+	// CashFlowsFromFinancingActivities is the financing cash flow in yen.
+	CashFlowsFromFinancingActivities *json.Number
+	// CashAndEquivalents is the cash and cash equivalents at the end of the period in yen.
+	CashAndEquivalents *json.Number
+	// ResultDividendPerShareAnnual is the actual annual dividend per share in yen.
+	ResultDividendPerShareAnnual *json.Number
+	// ForecastDividendPerShareAnnual is the forecast annual dividend per share in yen.
+	ForecastDividendPerShareAnnual *json.Number
+	// ForecastNetSales is the forecast net sales for the fiscal year in yen.
+	ForecastNetSales *json.Number
+	// ForecastOperatingProfit is the forecast operating profit for the fiscal year in yen.
+	ForecastOperatingProfit *json.Number
+	// ForecastOrdinaryProfit is the forecast ordinary profit for the fiscal year in yen.
+	ForecastOrdinaryProfit *json.Number
+	// ForecastProfit is the forecast profit for the fiscal year in yen.
+	ForecastProfit *json.Number
+	// ForecastEarningsPerShare is the forecast earnings per share for the fiscal year in yen.
+	ForecastEarningsPerShare *json.Number
+	// IssuedShares is the number of issued shares at the end of the period, including treasury stock.
+	IssuedShares *json.Number
+	// TreasuryShares is the number of treasury shares at the end of the period.
+	TreasuryShares *json.Number
+}
+
+func (fs *FinancialStatement) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		DisclosedDate                    string      `json:"DiscDate"`
+		DisclosedTime                    string      `json:"DiscTime"`
+		Code                             string      `json:"Code"`
+		DisclosureNumber                 string      `json:"DiscNo"`
+		TypeOfDocument                   string      `json:"DocType"`
+		TypeOfCurrentPeriod              string      `json:"CurPerType"`
+		CurrentPeriodStartDate           string      `json:"CurPerSt"`
+		CurrentPeriodEndDate             string      `json:"CurPerEn"`
+		CurrentFiscalYearStartDate       string      `json:"CurFYSt"`
+		CurrentFiscalYearEndDate         string      `json:"CurFYEn"`
+		NetSales                         interface{} `json:"Sales"`
+		OperatingProfit                  interface{} `json:"OP"`
+		OrdinaryProfit                   interface{} `json:"OdP"`
+		Profit                           interface{} `json:"NP"`
+		EarningsPerShare                 interface{} `json:"EPS"`
+		DilutedEarningsPerShare          interface{} `json:"DEPS"`
+		TotalAssets                      interface{} `json:"TA"`
+		Equity                           interface{} `json:"Eq"`
+		EquityToAssetRatio               interface{} `json:"EqAR"`
+		BookValuePerShare                interface{} `json:"BPS"`
+		CashFlowsFromOperatingActivities interface{} `json:"CFO"`
+		CashFlowsFromInvestingActivities interface{} `json:"CFI"`
+		CashFlowsFromFinancingActivities interface{} `json:"CFF"`
+		CashAndEquivalents               interface{} `json:"CashEq"`
+		ResultDividendPerShareAnnual     interface{} `json:"DivAnn"`
+		ForecastDividendPerShareAnnual   interface{} `json:"FDivAnn"`
+		ForecastNetSales                 interface{} `json:"FSales"`
+		ForecastOperatingProfit          interface{} `json:"FOP"`
+		ForecastOrdinaryProfit           interface{} `json:"FOdP"`
+		ForecastProfit                   interface{} `json:"FNP"`
+		ForecastEarningsPerShare         interface{} `json:"FEPS"`
+		IssuedShares                     interface{} `json:"ShOutFY"`
+		TreasuryShares                   interface{} `json:"TrShFY"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal financial statement: %w", err)
+	}
+
+	u := &unmarshaler{}
+
+	fs.DisclosedDate = raw.DisclosedDate
+	fs.DisclosedTime = raw.DisclosedTime
+	fs.Code = raw.Code
+	fs.DisclosureNumber = raw.DisclosureNumber
+	fs.TypeOfDocument = raw.TypeOfDocument
+	fs.TypeOfCurrentPeriod = raw.TypeOfCurrentPeriod
+	fs.CurrentPeriodStartDate = raw.CurrentPeriodStartDate
+	fs.CurrentPeriodEndDate = raw.CurrentPeriodEndDate
+	fs.CurrentFiscalYearStartDate = raw.CurrentFiscalYearStartDate
+	fs.CurrentFiscalYearEndDate = raw.CurrentFiscalYearEndDate
+	fs.NetSales = u.number(raw.NetSales)
+	fs.OperatingProfit = u.number(raw.OperatingProfit)
+	fs.OrdinaryProfit = u.number(raw.OrdinaryProfit)
+	fs.Profit = u.number(raw.Profit)
+	fs.EarningsPerShare = u.number(raw.EarningsPerShare)
+	fs.DilutedEarningsPerShare = u.number(raw.DilutedEarningsPerShare)
+	fs.TotalAssets = u.number(raw.TotalAssets)
+	fs.Equity = u.number(raw.Equity)
+	fs.EquityToAssetRatio = u.number(raw.EquityToAssetRatio)
+	fs.BookValuePerShare = u.number(raw.BookValuePerShare)
+	fs.CashFlowsFromOperatingActivities = u.number(raw.CashFlowsFromOperatingActivities)
+	fs.CashFlowsFromInvestingActivities = u.number(raw.CashFlowsFromInvestingActivities)
+	fs.CashFlowsFromFinancingActivities = u.number(raw.CashFlowsFromFinancingActivities)
+	fs.CashAndEquivalents = u.number(raw.CashAndEquivalents)
+	fs.ResultDividendPerShareAnnual = u.number(raw.ResultDividendPerShareAnnual)
+	fs.ForecastDividendPerShareAnnual = u.number(raw.ForecastDividendPerShareAnnual)
+	fs.ForecastNetSales = u.number(raw.ForecastNetSales)
+	fs.ForecastOperatingProfit = u.number(raw.ForecastOperatingProfit)
+	fs.ForecastOrdinaryProfit = u.number(raw.ForecastOrdinaryProfit)
+	fs.ForecastProfit = u.number(raw.ForecastProfit)
+	fs.ForecastEarningsPerShare = u.number(raw.ForecastEarningsPerShare)
+	fs.IssuedShares = u.number(raw.IssuedShares)
+	fs.TreasuryShares = u.number(raw.TreasuryShares)
+
+	if u.err != nil {
+		return fmt.Errorf("failed to unmarshal financial statement: %w", u.err)
+	}
+	return nil
+}
+
+// FinancialStatementsRequest specifies filter parameters for the FinancialStatements API.
+// Either Code or Date must be provided.
+type FinancialStatementsRequest struct {
+	// Code filters by security code. Required if Date is not specified.
+	Code *string
+	// Date filters by disclosure date in YYYY-MM-DD format. Required if Code is not specified.
+	Date *string
+	// AsOf keeps only the disclosures made on or before this date (YYYY-MM-DD), so a backtest sees what was
+	// known at that point without lookahead from later restatements. It is applied client-side to
+	// DisclosedDate and is not sent to the API. Optional.
+	AsOf *string
+}
+
+type financialStatementsParameters struct {
+	FinancialStatementsRequest
+	PaginationKey *string
+}
+
+func (p financialStatementsParameters) values() (url.Values, error) {
+	if err := errors.Join(checkDate("date", p.Date), checkDate("as of date", p.AsOf)); err != nil {
+		return nil, err
+	}
+	if p.Code == nil && p.Date == nil {
+		return nil, errors.New("code or date is required")
+	}
+	v := url.Values{}
+	if p.Code != nil {
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
+	}
+	if p.Date != nil {
+		v.Add("date", *p.Date)
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type financialStatementsResponse struct {
+	Data          records[FinancialStatement] `json:"data"`
+	PaginationKey *string                     `json:"pagination_key"`
+}
+
+func (r financialStatementsResponse) Items() []FinancialStatement     { return r.Data.items }
+func (r financialStatementsResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r financialStatementsResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendFinancialStatementsRequest(ctx context.Context, params financialStatementsParameters) (financialStatementsResponse, error) {
+	var r financialStatementsResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/fins/statements", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	if params.AsOf != nil {
+		r.Data.items = slices.DeleteFunc(r.Data.items, func(fs FinancialStatement) bool { return !disclosedBy(fs, *params.AsOf) })
+	}
+	return r, nil
+}
+
+// disclosedBy reports whether fs was disclosed on or before the date asOf.
+func disclosedBy(fs FinancialStatement, asOf string) bool {
+	return fs.DisclosedDate <= asOf
+}
+
+// FinancialStatements retrieves quarterly and annual financial statements from the /fins/statements endpoint.
+// It automatically handles pagination to fetch all matching records.
+func (c *Client) FinancialStatements(ctx context.Context, req FinancialStatementsRequest) ([]FinancialStatement, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (financialStatementsResponse, error) {
+		params := financialStatementsParameters{FinancialStatementsRequest: req, PaginationKey: paginationKey}
+		return c.sendFinancialStatementsRequest(ctx, params)
+	})
+}
+
+// FinancialStatementsWithChannel retrieves financial statements and streams each record to the provided channel.
+// The channel is closed when all records have been sent or an error occurs.
+func (c *Client) FinancialStatementsWithChannel(ctx context.Context, req FinancialStatementsRequest, ch chan<- FinancialStatement) error {
+	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string, emit func(FinancialStatement) error) (streamedPage[FinancialStatement], error) {
+		params := financialStatementsParameters{FinancialStatementsRequest: req, PaginationKey: paginationKey}
+		if req.AsOf != nil {
+			send := emit
+			emit = func(fs FinancialStatement) error {
+				if !disclosedBy(fs, *req.AsOf) {
+					return nil
+				}
+				return send(fs)
+			}
+		}
+		return streamPage(ctx, c, "/fins/statements", params, emit)
+	})
+}
+
+// ForecastRevision is a change of one full-year forecast between two consecutive disclosures of the same
+// fiscal year, as reported by [DetectForecastRevisions].
+type ForecastRevision struct {
+	// Code is the security code (ticker symbol).
+	Code string
+	// FiscalYearEndDate is the end of the forecast fiscal year in YYYY-MM-DD format.
+	FiscalYearEndDate string
+	// DisclosedDate is the disclosure date of the revised forecast in YYYY-MM-DD format.
+	DisclosedDate string
+	// DisclosureNumber is the disclosure number of the revised forecast.
+	DisclosureNumber string
+	// Field is the name of the revised FinancialStatement field (e.g., "ForecastNetSales").
+	Field string
+	// Previous is the forecast of the preceding disclosure.
+	Previous json.Number
+	// Revised is the forecast of this disclosure.
+	Revised json.Number
+	// Change is Revised - Previous.
+	Change json.Number
+	// ChangeRatio is Change divided by |Previous| (e.g., 0.1 for a 10% upward revision); zero if Previous is zero.
+	ChangeRatio float64
+	// Direction is 1 for an upward revision and -1 for a downward one.
+	Direction int
+}
+
+// forecastFields are the FinancialStatement forecasts compared by DetectForecastRevisions.
+var forecastFields = []struct {
+	name  string
+	value func(FinancialStatement) *json.Number
+}{
+	{"ForecastNetSales", func(fs FinancialStatement) *json.Number { return fs.ForecastNetSales }},
+	{"ForecastOperatingProfit", func(fs FinancialStatement) *json.Number { return fs.ForecastOperatingProfit }},
+	{"ForecastOrdinaryProfit", func(fs FinancialStatement) *json.Number { return fs.ForecastOrdinaryProfit }},
+	{"ForecastProfit", func(fs FinancialStatement) *json.Number { return fs.ForecastProfit }},
+	{"ForecastEarningsPerShare", func(fs FinancialStatement) *json.Number { return fs.ForecastEarningsPerShare }},
+}
+
+// DetectForecastRevisions compares consecutive disclosures of the same code and fiscal year
+// (CurrentFiscalYearEndDate) and reports each forecast net sales, operating profit, ordinary profit, profit, or
+// EPS that changed. Disclosures are ordered by disclosure date, time, and number, so statements may be unordered
+// and contain several codes. The first disclosure of a fiscal year is the baseline, not a revision, and a blank
+// or unparsable forecast neither counts as a revision nor replaces the previous value. Values are compared
+// exactly, so "100" and "100.0" are equal. The result is sorted by disclosure date, code, and field order.
+func DetectForecastRevisions(statements []FinancialStatement) []ForecastRevision {
+	sorted := slices.Clone(statements)
+	slices.SortStableFunc(sorted, func(a, b FinancialStatement) int {
+		return cmp.Or(
+			cmp.Compare(a.DisclosedDate, b.DisclosedDate),
+			cmp.Compare(a.DisclosedTime, b.DisclosedTime),
+			cmp.Compare(a.DisclosureNumber, b.DisclosureNumber),
+		)
+	})
+	type period struct{ code, fiscalYearEnd string }
+	latest := make(map[period][]*big.Rat)
+	revisions := make([]ForecastRevision, 0)
+	for _, fs := range sorted {
+		k := period{fs.Code, fs.CurrentFiscalYearEndDate}
+		previous, ok := latest[k]
+		if !ok {
+			previous = make([]*big.Rat, len(forecastFields))
+			latest[k] = previous
+		}
+		for i, field := range forecastFields {
+			n := field.value(fs)
+			if n == nil {
+				continue
+			}
+			revised, ok := new(big.Rat).SetString(n.String())
+			if !ok {
+				continue
+			}
+			if prev := previous[i]; prev != nil && prev.Cmp(revised) != 0 {
+				change := new(big.Rat).Sub(revised, prev)
+				var ratio float64
+				if prev.Sign() != 0 {
+					ratio, _ = new(big.Rat).Quo(change, new(big.Rat).Abs(prev)).Float64()
+				}
+				revisions = append(revisions, ForecastRevision{
+					Code:              fs.Code,
+					FiscalYearEndDate: fs.CurrentFiscalYearEndDate,
+					DisclosedDate:     fs.DisclosedDate,
+					DisclosureNumber:  fs.DisclosureNumber,
+					Field:             field.name,
+					Previous:          formatRat(prev),
+					Revised:           *n,
+					Change:            formatRat(change),
+					ChangeRatio:       ratio,
+					Direction:         change.Sign(),
+				})
+			}
+			previous[i] = revised
+		}
+	}
+	slices.SortStableFunc(revisions, func(a, b ForecastRevision) int {
+		return cmp.Or(cmp.Compare(a.DisclosedDate, b.DisclosedDate), cmp.Compare(a.Code, b.Code))
+	})
+	return revisions
+}
+
+// Dividend represents a dividend announcement (forecast or actual) of a listed company.
+// Amounts are nil when the announcement leaves them blank (e.g., an undetermined forecast).
+type Dividend struct {
+	// AnnouncementDate is the announcement date in YYYY-MM-DD format.
+	AnnouncementDate string
+	// AnnouncementTime is the announcement time in HH:MM format.
+	AnnouncementTime string
+	// Code is the security code (ticker symbol).
+	Code string
+	// ReferenceNumber is the unique number of the announcement.
+	ReferenceNumber string
+	// StatusCode is the announcement status (1: new, 2: revised, 3: deleted).
+	StatusCode string
+	// BoardMeetingDate is the date of the board meeting in YYYY-MM-DD format.
+	BoardMeetingDate string
+	// InterimFinalCode indicates the dividend type (1: interim, 2: year-end).
+	InterimFinalCode string
+	// ForecastResultCode indicates whether the record is a result (1) or a forecast (2).
+	ForecastResultCode string
+	// InterimFinalTerm is the fiscal period the dividend belongs to in YYYY-MM format.
+	InterimFinalTerm string
+	// DistributionAmount is the dividend per share in yen.
+	DistributionAmount *json.Number
+	// RecordDate is the record date in YYYY-MM-DD format.
+	RecordDate string
+	// ExDate is the ex-dividend date in YYYY-MM-DD format.
+	ExDate string
+	// ActualRecordDate is the date on which the shareholder register is fixed in YYYY-MM-DD format.
+	ActualRecordDate string
+	// PayableDate is the scheduled payment start date in YYYY-MM-DD format (nil if undetermined).
+	PayableDate *string
+	// CAReferenceNumber is the reference number of the original announcement this record revises.
+	CAReferenceNumber string
+	// CommemorativeSpecialCode indicates a commemorative or special dividend (0: normal, 1: commemorative,
+	// 2: special, 3: both).
+	CommemorativeSpecialCode string
+}
+
+func (d *Dividend) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		AnnouncementDate         string      `json:"PubDate"`
+		AnnouncementTime         string      `json:"PubTime"`
+		Code                     string      `json:"Code"`
+		ReferenceNumber          string      `json:"RefNo"`
+		StatusCode               string      `json:"StatCode"`
+		BoardMeetingDate         string      `json:"BoardDate"`
+		InterimFinalCode         string      `json:"IFCode"`
+		ForecastResultCode       string      `json:"FRCode"`
+		InterimFinalTerm         string      `json:"IFTerm"`
+		DistributionAmount       interface{} `json:"DivRate"`
+		RecordDate               string      `json:"RecDate"`
+		ExDate                   string      `json:"ExDate"`
+		ActualRecordDate         string      `json:"ActRecDate"`
+		PayableDate              string      `json:"PayDate"`
+		CAReferenceNumber        string      `json:"CARefNo"`
+		CommemorativeSpecialCode string      `json:"CommSpecCode"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal dividend: %w", err)
+	}
+	amount, err := unmarshalNumber(raw.DistributionAmount)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal dividend: %w", err)
+	}
+	d.AnnouncementDate = raw.AnnouncementDate
+	d.AnnouncementTime = raw.AnnouncementTime
+	d.Code = raw.Code
+	d.ReferenceNumber = raw.ReferenceNumber
+	d.StatusCode = raw.StatusCode
+	d.BoardMeetingDate = raw.BoardMeetingDate
+	d.InterimFinalCode = raw.InterimFinalCode
+	d.ForecastResultCode = raw.ForecastResultCode
+	d.InterimFinalTerm = raw.InterimFinalTerm
+	d.DistributionAmount = amount
+	d.RecordDate = raw.RecordDate
+	d.ExDate = raw.ExDate
+	d.ActualRecordDate = raw.ActualRecordDate
+	d.PayableDate = unmarshalTime(raw.PayableDate)
+	d.CAReferenceNumber = raw.CAReferenceNumber
+	d.CommemorativeSpecialCode = raw.CommemorativeSpecialCode
+	return nil
+}
+
+// DividendRequest specifies filter parameters for the Dividend API.
+// Either Code or Date must be provided.
+type DividendRequest struct {
+	// Code filters by security code. Required if Date is not specified.
+	Code *string
+	// Date filters by announcement date in YYYY-MM-DD format. If specified, Code is ignored.
+	Date *string
+	// From specifies the start of an announcement date range (used with Code).
+	From *string
+	// To specifies the end of an announcement date range (used with Code).
+	To *string
+}
+
+type dividendParameters struct {
+	DividendRequest
+	PaginationKey *string
+}
+
+func (p dividendParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	if p.Date != nil {
+		v.Add("date", *p.Date)
+	} else {
+		if p.Code == nil {
+			return nil, errors.New("code or date is required")
+		}
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
+		if p.From != nil {
+			v.Add("from", *p.From)
+		}
+		if p.To != nil {
+			v.Add("to", *p.To)
+		}
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type dividendResponse struct {
+	Data          records[Dividend] `json:"data"`
+	PaginationKey *string           `json:"pagination_key"`
+}
+
+func (r dividendResponse) Items() []Dividend               { return r.Data.items }
+func (r dividendResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r dividendResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendDividendRequest(ctx context.Context, params dividendParameters) (dividendResponse, error) {
+	var r dividendResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/fins/dividend", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// Dividend retrieves dividend announcements from the /fins/dividend endpoint.
+// It automatically handles pagination to fetch all matching records.
+func (c *Client) Dividend(ctx context.Context, req DividendRequest) ([]Dividend, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (dividendResponse, error) {
+		params := dividendParameters{DividendRequest: req, PaginationKey: paginationKey}
+		return c.sendDividendRequest(ctx, params)
+	})
+}
+
+// EarningsAnnouncement represents a scheduled earnings announcement of a listed company.
+type EarningsAnnouncement struct {
+	// Date is the scheduled announcement date in YYYY-MM-DD format.
+	Date string
+	// Code is the security code (ticker symbol).
+	Code string
+	// CompanyName is the company name in Japanese.
+	CompanyName string
+	// FiscalYear is the fiscal year end of the results (e.g., "3月31日").
+	FiscalYear string
+	// SectorName is the industry sector name.
+	SectorName string
+	// FiscalQuarter is the fiscal quarter of the results (e.g., "第1四半期").
+	FiscalQuarter string
+	// Section is the market section name.
+	Section string
+}
+
+func (a *EarningsAnnouncement) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Date          string `json:"Date"`
+		Code          string `json:"Code"`
+		CompanyName   string `json:"CoName"`
+		FiscalYear    string `json:"FY"`
+		SectorName    string `json:"SectorNm"`
+		FiscalQuarter string `json:"FQ"`
+		Section       string `json:"Section"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal earnings announcement: %w", err)
+	}
+	a.Date = raw.Date
+	a.Code = raw.Code
+	a.CompanyName = raw.CompanyName
+	a.FiscalYear = raw.FiscalYear
+	a.SectorName = raw.SectorName
+	a.FiscalQuarter = raw.FiscalQuarter
+	a.Section = raw.Section
+	return nil
+}
+
+// EarningsAnnouncementRequest specifies parameters for the EarningsAnnouncement API.
+// The endpoint takes no filters and always returns the whole upcoming schedule.
+type EarningsAnnouncementRequest struct{}
+
+type earningsAnnouncementParameters struct {
+	EarningsAnnouncementRequest
+	PaginationKey *string
+}
+
+func (p earningsAnnouncementParameters) values() (url.Values, error) {
+	v := url.Values{}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type earningsAnnouncementResponse struct {
+	Data          records[EarningsAnnouncement] `json:"data"`
+	PaginationKey *string                       `json:"pagination_key"`
+}
+
+func (r earningsAnnouncementResponse) Items() []EarningsAnnouncement   { return r.Data.items }
+func (r earningsAnnouncementResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r earningsAnnouncementResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendEarningsAnnouncementRequest(ctx context.Context, params earningsAnnouncementParameters) (earningsAnnouncementResponse, error) {
+	var r earningsAnnouncementResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/fins/announcement", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// EarningsAnnouncement retrieves the upcoming earnings announcement schedule from the /fins/announcement endpoint.
+// It automatically handles pagination to fetch all matching records.
+func (c *Client) EarningsAnnouncement(ctx context.Context, req EarningsAnnouncementRequest) ([]EarningsAnnouncement, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (earningsAnnouncementResponse, error) {
+		params := earningsAnnouncementParameters{EarningsAnnouncementRequest: req, PaginationKey: paginationKey}
+		return c.sendEarningsAnnouncementRequest(ctx, params)
+	})
+}
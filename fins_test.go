@@ -0,0 +1,160 @@
+package jquants
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestClient_FinancialStatements(t *testing.T) {
+	code := "72030"
+	client := setupClient(t)
+	res, err := client.FinancialStatements(t.Context(), FinancialStatementsRequest{Code: &code})
+	if err != nil {
+		t.Errorf("Failed to get financial statements: %s", err)
+	}
+	if len(res) == 0 {
+		t.Error("Empty financial statements")
+	}
+}
+
+func TestFinancialStatement_UnmarshalJSON(t *testing.T) {
+	data := `{"DiscDate":"2024-05-08","Code":"72030","CurPerType":"FY","Sales":"45095325000000","OP":"5352934000000","EPS":"365.94","FSales":"","TA":1.5e3}`
+	var fs FinancialStatement
+	if err := json.Unmarshal([]byte(data), &fs); err != nil {
+		t.Fatalf("Failed to unmarshal financial statement: %v", err)
+	}
+	if fs.NetSales == nil || *fs.NetSales != "45095325000000" || fs.EarningsPerShare == nil || *fs.EarningsPerShare != "365.94" {
+		t.Errorf("Unexpected numeric fields: sales=%v, eps=%v", fs.NetSales, fs.EarningsPerShare)
+	}
+	if fs.ForecastNetSales != nil || fs.Profit != nil {
+		t.Errorf("Expected empty and missing fields to be nil: forecast=%v, profit=%v", fs.ForecastNetSales, fs.Profit)
+	}
+	if fs.TotalAssets == nil || *fs.TotalAssets != "1500" {
+		t.Errorf("Unexpected total assets: %v", fs.TotalAssets)
+	}
+	if err := json.Unmarshal([]byte(`{"Sales":"n/a"}`), &fs); err == nil {
+		t.Error("Expected error for a non-numeric value")
+	}
+}
+
+func TestFinancialStatementsParameters(t *testing.T) {
+	if _, err := (financialStatementsParameters{}).values(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+}
+
+func TestFinancialStatements_AsOf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("as_of") || r.URL.Query().Has("asof") {
+			t.Errorf("Expected AsOf not to be sent: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"data":[
+			{"DiscDate":"2024-05-08","Code":"72030","DiscNo":"1"},
+			{"DiscDate":"2024-08-01","Code":"72030","DiscNo":"2"},
+			{"DiscDate":"2024-11-06","Code":"72030","DiscNo":"3"}
+		]}`)
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil)
+	code, asOf := "72030", "2024-08-01"
+	req := FinancialStatementsRequest{Code: &code, AsOf: &asOf}
+	statements, err := client.FinancialStatements(t.Context(), req)
+	if err != nil {
+		t.Fatalf("Failed to get financial statements: %v", err)
+	}
+	if len(statements) != 2 || statements[1].DisclosureNumber != "2" {
+		t.Errorf("Expected the disclosures up to and including %s: %+v", asOf, statements)
+	}
+
+	ch := make(chan FinancialStatement)
+	errCh := make(chan error, 1)
+	go func() { errCh <- client.FinancialStatementsWithChannel(t.Context(), req, ch) }()
+	var streamed []string
+	for fs := range ch {
+		streamed = append(streamed, fs.DisclosureNumber)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Failed to stream financial statements: %v", err)
+	}
+	if !slices.Equal(streamed, []string{"1", "2"}) {
+		t.Errorf("Unexpected streamed disclosures: %v", streamed)
+	}
+
+	invalid := "20240801"
+	if _, err := (financialStatementsParameters{FinancialStatementsRequest: FinancialStatementsRequest{Code: &code, AsOf: &invalid}}).values(); err == nil {
+		t.Error("Expected error for an AsOf date not in YYYY-MM-DD format")
+	}
+}
+
+func TestDetectForecastRevisions(t *testing.T) {
+	statements := []FinancialStatement{
+		{Code: "72030", DisclosedDate: "2024-08-01", DisclosureNumber: "2", CurrentFiscalYearEndDate: "2025-03-31", ForecastNetSales: number("46000000000000"), ForecastEarningsPerShare: number("250")},
+		{Code: "72030", DisclosedDate: "2024-05-08", DisclosureNumber: "1", CurrentFiscalYearEndDate: "2025-03-31", ForecastNetSales: number("45000000000000"), ForecastEarningsPerShare: number("250.0")},
+		{Code: "72030", DisclosedDate: "2024-11-06", DisclosureNumber: "3", CurrentFiscalYearEndDate: "2025-03-31", ForecastEarningsPerShare: number("200")},
+		{Code: "72030", DisclosedDate: "2025-05-08", DisclosureNumber: "4", CurrentFiscalYearEndDate: "2026-03-31", ForecastNetSales: number("48000000000000")},
+		{Code: "67580", DisclosedDate: "2024-08-07", DisclosureNumber: "5", CurrentFiscalYearEndDate: "2025-03-31", ForecastProfit: number("0")},
+		{Code: "67580", DisclosedDate: "2024-11-08", DisclosureNumber: "6", CurrentFiscalYearEndDate: "2025-03-31", ForecastProfit: number("100")},
+	}
+	got := DetectForecastRevisions(statements)
+	want := []ForecastRevision{
+		{Code: "72030", FiscalYearEndDate: "2025-03-31", DisclosedDate: "2024-08-01", DisclosureNumber: "2", Field: "ForecastNetSales",
+			Previous: "45000000000000", Revised: "46000000000000", Change: "1000000000000", ChangeRatio: 1.0 / 45, Direction: 1},
+		{Code: "72030", FiscalYearEndDate: "2025-03-31", DisclosedDate: "2024-11-06", DisclosureNumber: "3", Field: "ForecastEarningsPerShare",
+			Previous: "250", Revised: "200", Change: "-50", ChangeRatio: -0.2, Direction: -1},
+		{Code: "67580", FiscalYearEndDate: "2025-03-31", DisclosedDate: "2024-11-08", DisclosureNumber: "6", Field: "ForecastProfit",
+			Previous: "0", Revised: "100", Change: "100", ChangeRatio: 0, Direction: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Unexpected revisions:\ngot  %+v\nwant %+v", got, want)
+	}
+	if got := DetectForecastRevisions(statements[:1]); len(got) != 0 {
+		t.Errorf("Expected the first disclosure to be a baseline, got %+v", got)
+	}
+}
+
+func TestClient_Dividend(t *testing.T) {
+	code := "72030"
+	client := setupClient(t)
+	res, err := client.Dividend(t.Context(), DividendRequest{Code: &code})
+	if err != nil {
+		t.Errorf("Failed to get dividends: %s", err)
+	}
+	if len(res) == 0 {
+		t.Error("Empty dividends")
+	}
+}
+
+func TestDividend_UnmarshalJSON(t *testing.T) {
+	var d Dividend
+	if err := json.Unmarshal([]byte(`{"PubDate":"2024-05-08","Code":"72030","DivRate":"","RecDate":"2025-03-31","PayDate":""}`), &d); err != nil {
+		t.Fatalf("Failed to unmarshal dividend: %v", err)
+	}
+	if d.DistributionAmount != nil || d.PayableDate != nil {
+		t.Errorf("Expected undetermined fields to be nil: %+v", d)
+	}
+	if err := json.Unmarshal([]byte(`{"PubDate":"2024-05-08","Code":"72030","DivRate":"45.5"}`), &d); err != nil || d.DistributionAmount == nil || *d.DistributionAmount != "45.5" {
+		t.Errorf("Unexpected distribution amount: %v, %v", d.DistributionAmount, err)
+	}
+}
+
+func TestClient_EarningsAnnouncement(t *testing.T) {
+	client := setupClient(t)
+	if _, err := client.EarningsAnnouncement(t.Context(), EarningsAnnouncementRequest{}); err != nil {
+		t.Errorf("Failed to get earnings announcement: %v", err)
+	}
+}
+
+func TestEarningsAnnouncement_UnmarshalJSON(t *testing.T) {
+	data := `{"Date":"2025-02-06","Code":"72030","CoName":"トヨタ自動車","FY":"3月31日","SectorNm":"輸送用機器","FQ":"第3四半期","Section":"プライム"}`
+	var a EarningsAnnouncement
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		t.Fatalf("Failed to unmarshal earnings announcement: %v", err)
+	}
+	if a.Date != "2025-02-06" || a.CompanyName != "トヨタ自動車" || a.SectorName != "輸送用機器" || a.FiscalQuarter != "第3四半期" || a.Section != "プライム" {
+		t.Errorf("Unexpected earnings announcement: %+v", a)
+	}
+}
@@ -0,0 +1,168 @@
+package jquants
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RoundTripFunc is an http.RoundTripper backed by a function, for test doubles of the J-Quants API.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fixtureBasePath is the API version prefix of BaseURL, stripped from request paths to find fixtures.
+var fixtureBasePath = func() string {
+	u, _ := url.Parse(BaseURL)
+	return u.Path
+}()
+
+// fixtureClient returns a client whose requests are answered with the gzipped JSON files in testdata.
+// The fixture of an endpoint is its path with slashes replaced by underscores (e.g., equities_bars_daily.json);
+// a request with a pagination key is answered with the file suffixed by "_" and the key. The requested
+// query strings are appended to *queries.
+func fixtureClient(t *testing.T, queries *[]string) *Client {
+	t.Helper()
+	transport := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*queries = append(*queries, req.URL.RawQuery)
+		name := strings.ReplaceAll(strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, fixtureBasePath), "/"), "/", "_")
+		if key := req.URL.Query().Get("pagination_key"); key != "" {
+			name += "_" + key
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+		if err != nil {
+			t.Errorf("No fixture for %s: %v", req.URL.Path, err)
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message":"no fixture"}`)), Request: req}, nil
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		header := http.Header{"Content-Type": {"application/json"}, "Content-Encoding": {"gzip"}}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(&buf), Request: req}, nil
+	})
+	return NewTestClient(BaseURL, "test", &http.Client{Transport: transport})
+}
+
+func TestFixture_IssueInformation(t *testing.T) {
+	var queries []string
+	code := "72030"
+	issues, err := fixtureClient(t, &queries).IssueInformation(t.Context(), IssueInformationRequest{Code: &code})
+	if err != nil {
+		t.Fatalf("Failed to get issue information: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("Unexpected number of issues: %d", len(issues))
+	}
+	ii := issues[0]
+	if ii.CompanyNameEnglish != "TOYOTA MOTOR CORPORATION" || ii.Sector17Code != 6 || ii.Sector33Code != "3700" || ii.MarketCode != "0111" {
+		t.Errorf("Unexpected issue: %+v", ii)
+	}
+	if ii.MarginCode == nil || *ii.MarginCode != 2 || ii.MarginName == nil || *ii.MarginName != "貸借" {
+		t.Errorf("Unexpected margin classification: %v %v", ii.MarginCode, ii.MarginName)
+	}
+}
+
+func TestFixture_StockPrice(t *testing.T) {
+	var queries []string
+	date := "2024-05-10"
+	prices, err := fixtureClient(t, &queries).StockPrice(t.Context(), StockPriceRequest{Date: &date})
+	if err != nil {
+		t.Fatalf("Failed to get stock prices: %v", err)
+	}
+	if len(queries) != 2 || !strings.Contains(queries[1], "pagination_key=page2") {
+		t.Errorf("Expected the second page to be requested with its key: %v", queries)
+	}
+	if len(prices) != 3 {
+		t.Fatalf("Expected records from both pages, got %d", len(prices))
+	}
+	toyota := prices[1]
+	if toyota.Code != "72030" || toyota.Close == nil || *toyota.Close != "3410.0" {
+		t.Errorf("Unexpected price: %+v", toyota)
+	}
+	if toyota.TurnoverValue == nil || *toyota.TurnoverValue != 106268574950 {
+		t.Errorf("Turnover value not kept exact: %v", toyota.TurnoverValue)
+	}
+	suspended := prices[2]
+	if suspended.Open != nil || suspended.Close != nil || suspended.Volume != nil {
+		t.Errorf("Expected nil prices for a day without trades: %+v", suspended)
+	}
+}
+
+func TestFixture_InvestorType(t *testing.T) {
+	var queries []string
+	section := "TSEPrime"
+	investorTypes, err := fixtureClient(t, &queries).InvestorType(t.Context(), InvestorTypeRequest{Section: &section})
+	if err != nil {
+		t.Fatalf("Failed to get investor types: %v", err)
+	}
+	if len(investorTypes) != 1 {
+		t.Fatalf("Unexpected number of records: %d", len(investorTypes))
+	}
+	it := investorTypes[0]
+	if it.PublishedDate != "2024-05-16" || it.Section != "TSEPrime" {
+		t.Errorf("Unexpected record: %+v", it)
+	}
+	want := TradingBalance{Sales: "1000000012", Purchases: "2000000012", Total: "3000000024", Balance: "1000000000"}
+	if it.OtherFinancialInstitutions != want {
+		t.Errorf("Unexpected balance: got %+v, want %+v", it.OtherFinancialInstitutions, want)
+	}
+	if it.Proprietary.Sales != "1000000000" {
+		t.Errorf("Unexpected proprietary sales: %s", it.Proprietary.Sales)
+	}
+}
+
+func TestFixture_TradingCalendar(t *testing.T) {
+	var queries []string
+	from, to := "2024-05-03", "2024-05-07"
+	calendar, err := fixtureClient(t, &queries).TradingCalendar(t.Context(), TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("Failed to get trading calendar: %v", err)
+	}
+	if len(calendar) != 2 || calendar[0].DayType != 0 || calendar[1].DayType != 1 {
+		t.Errorf("Unexpected calendar: %+v", calendar)
+	}
+}
+
+func TestFixture_IndexPrice(t *testing.T) {
+	var queries []string
+	code := "0028"
+	prices, err := fixtureClient(t, &queries).IndexPrice(t.Context(), IndexPriceRequest{Code: &code})
+	if err != nil {
+		t.Fatalf("Failed to get index prices: %v", err)
+	}
+	if len(prices) != 1 || prices[0].Close != json.Number("1281.99") {
+		t.Errorf("Unexpected index prices: %+v", prices)
+	}
+}
+
+func TestFixture_IndexOptionPrice(t *testing.T) {
+	var queries []string
+	prices, err := fixtureClient(t, &queries).IndexOptionPrice(t.Context(), IndexOptionPriceRequest{Date: "2024-05-10"})
+	if err != nil {
+		t.Fatalf("Failed to get index option prices: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Unexpected number of prices: %d", len(prices))
+	}
+	put := prices[1]
+	if put.PutCallDivision != 2 || put.StrikePrice != 20000 || put.OpenInterest != 330 || put.TurnoverValue != 224980000 {
+		t.Errorf("Unexpected option price: %+v", put)
+	}
+	if put.WholeDayClose == nil || *put.WholeDayClose != 18800 || put.SettlementPrice == nil || *put.SettlementPrice != 18780 {
+		t.Errorf("Unexpected prices: close %v, settlement %v", put.WholeDayClose, put.SettlementPrice)
+	}
+	if put.ImpliedVolatility == nil || *put.ImpliedVolatility != "20.1012" {
+		t.Errorf("Unexpected implied volatility: %v", put.ImpliedVolatility)
+	}
+	if put.LastTradingDay == nil || *put.LastTradingDay != "2024-06-13" {
+		t.Errorf("Unexpected last trading day: %v", put.LastTradingDay)
+	}
+}
@@ -0,0 +1,255 @@
+package jquants
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// FuturesPrice represents daily price data for a futures contract, with prices for the whole day,
+// the night session, and the day session.
+// Prices are json.Number rather than integers because several products (e.g., TOPIX and JGB futures) trade
+// in fractional ticks. Price fields are nil when the session did not trade.
+type FuturesPrice struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// Code is the futures contract code.
+	Code string
+	// DerivativeProductCategory is the product category (e.g., "TOPIXF", "NK225F", "NK225MF").
+	DerivativeProductCategory string
+	// WholeDayOpen is the opening price for the whole trading day.
+	WholeDayOpen *json.Number
+	// WholeDayHigh is the highest price for the whole trading day.
+	WholeDayHigh *json.Number
+	// WholeDayLow is the lowest price for the whole trading day.
+	WholeDayLow *json.Number
+	// WholeDayClose is the closing price for the whole trading day.
+	WholeDayClose *json.Number
+	// NightSessionOpen is the opening price for the night session.
+	NightSessionOpen *json.Number
+	// NightSessionHigh is the highest price for the night session.
+	NightSessionHigh *json.Number
+	// NightSessionLow is the lowest price for the night session.
+	NightSessionLow *json.Number
+	// NightSessionClose is the closing price for the night session.
+	NightSessionClose *json.Number
+	// DaySessionOpen is the opening price for the day session.
+	DaySessionOpen *json.Number
+	// DaySessionHigh is the highest price for the day session.
+	DaySessionHigh *json.Number
+	// DaySessionLow is the lowest price for the day session.
+	DaySessionLow *json.Number
+	// DaySessionClose is the closing price for the day session.
+	DaySessionClose *json.Number
+	// Volume is the total trading volume in contracts.
+	Volume int64
+	// OpenInterest is the number of outstanding contracts.
+	OpenInterest int64
+	// TurnoverValue is the total trading value in yen.
+	TurnoverValue int64
+	// ContractMonth is the contract expiration month in YYYY-MM format.
+	ContractMonth string
+	// VolumeOnlyAuction is the volume from auction-only trades.
+	VolumeOnlyAuction *int64
+	// EmergencyMarginTriggerDivision indicates emergency margin status.
+	EmergencyMarginTriggerDivision string
+	// LastTradingDay is the last trading day for this contract.
+	LastTradingDay *string
+	// SpecialQuotationDay is the special quotation day (SQ day).
+	SpecialQuotationDay *string
+	// SettlementPrice is the daily settlement price.
+	SettlementPrice *json.Number
+	// CentralContractMonthFlag reports whether this is the central (most actively traded) contract month.
+	CentralContractMonthFlag bool
+}
+
+func (fp *FuturesPrice) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Date                           string      `json:"Date"`
+		Code                           string      `json:"Code"`
+		DerivativeProductCategory      string      `json:"ProdCat"`
+		WholeDayOpen                   interface{} `json:"O"`
+		WholeDayHigh                   interface{} `json:"H"`
+		WholeDayLow                    interface{} `json:"L"`
+		WholeDayClose                  interface{} `json:"C"`
+		NightSessionOpen               interface{} `json:"EO"`
+		NightSessionHigh               interface{} `json:"EH"`
+		NightSessionLow                interface{} `json:"EL"`
+		NightSessionClose              interface{} `json:"EC"`
+		DaySessionOpen                 interface{} `json:"AO"`
+		DaySessionHigh                 interface{} `json:"AH"`
+		DaySessionLow                  interface{} `json:"AL"`
+		DaySessionClose                interface{} `json:"AC"`
+		Volume                         json.Number `json:"Vo"`
+		OpenInterest                   json.Number `json:"OI"`
+		TurnoverValue                  json.Number `json:"Va"`
+		ContractMonth                  string      `json:"CM"`
+		VolumeOnlyAuction              interface{} `json:"VoOA"`
+		EmergencyMarginTriggerDivision string      `json:"EmMrgnTrgDiv"`
+		LastTradingDay                 string      `json:"LTD"`
+		SpecialQuotationDay            string      `json:"SQD"`
+		SettlementPrice                interface{} `json:"Settle"`
+		CentralContractMonthFlag       string      `json:"CCMFlag"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal futures price: %w", err)
+	}
+
+	u := &unmarshaler{}
+
+	fp.Date = raw.Date
+	fp.Code = raw.Code
+	fp.DerivativeProductCategory = raw.DerivativeProductCategory
+	fp.WholeDayOpen = u.jsonNumber(raw.WholeDayOpen)
+	fp.WholeDayHigh = u.jsonNumber(raw.WholeDayHigh)
+	fp.WholeDayLow = u.jsonNumber(raw.WholeDayLow)
+	fp.WholeDayClose = u.jsonNumber(raw.WholeDayClose)
+	fp.NightSessionOpen = u.jsonNumber(raw.NightSessionOpen)
+	fp.NightSessionHigh = u.jsonNumber(raw.NightSessionHigh)
+	fp.NightSessionLow = u.jsonNumber(raw.NightSessionLow)
+	fp.NightSessionClose = u.jsonNumber(raw.NightSessionClose)
+	fp.DaySessionOpen = u.jsonNumber(raw.DaySessionOpen)
+	fp.DaySessionHigh = u.jsonNumber(raw.DaySessionHigh)
+	fp.DaySessionLow = u.jsonNumber(raw.DaySessionLow)
+	fp.DaySessionClose = u.jsonNumber(raw.DaySessionClose)
+	fp.Volume = u.integer(raw.Volume)
+	fp.OpenInterest = u.integer(raw.OpenInterest)
+	fp.TurnoverValue = u.integer(raw.TurnoverValue)
+	fp.ContractMonth = raw.ContractMonth
+	fp.VolumeOnlyAuction = u.volume(raw.VolumeOnlyAuction)
+	fp.EmergencyMarginTriggerDivision = raw.EmergencyMarginTriggerDivision
+	fp.LastTradingDay = unmarshalTime(raw.LastTradingDay)
+	fp.SpecialQuotationDay = unmarshalTime(raw.SpecialQuotationDay)
+	fp.SettlementPrice = u.jsonNumber(raw.SettlementPrice)
+	fp.CentralContractMonthFlag = raw.CentralContractMonthFlag == "1"
+
+	return u.err
+}
+
+// FuturesPriceRequest specifies filter parameters for the FuturesPrice API.
+type FuturesPriceRequest struct {
+	// Date is the trading date to query in YYYY-MM-DD format. Required.
+	Date string
+	// Category filters by derivative product category (e.g., "TOPIXF"). Optional.
+	Category *string
+}
+
+type futuresPriceParameters struct {
+	FuturesPriceRequest
+	PaginationKey *string
+}
+
+func (p futuresPriceParameters) values() (url.Values, error) {
+	if p.Date == "" {
+		return nil, errors.New("date is required")
+	}
+	if err := checkDate("date", &p.Date); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("date", p.Date)
+	if p.Category != nil {
+		v.Add("category", *p.Category)
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type futuresPriceResponse struct {
+	Data          records[FuturesPrice] `json:"data"`
+	PaginationKey *string               `json:"pagination_key"`
+}
+
+func (r futuresPriceResponse) Items() []FuturesPrice           { return r.Data.items }
+func (r futuresPriceResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r futuresPriceResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendFuturesPriceRequest(ctx context.Context, params futuresPriceParameters) (futuresPriceResponse, error) {
+	var r futuresPriceResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/derivatives/bars/daily/futures", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// FuturesPrice retrieves futures prices from the /derivatives/bars/daily/futures endpoint.
+// It automatically handles pagination to fetch all matching records.
+func (c *Client) FuturesPrice(ctx context.Context, req FuturesPriceRequest) ([]FuturesPrice, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (futuresPriceResponse, error) {
+		params := futuresPriceParameters{FuturesPriceRequest: req, PaginationKey: paginationKey}
+		return c.sendFuturesPriceRequest(ctx, params)
+	})
+}
+
+// FuturesPriceWithChannel retrieves futures prices and streams each record to the provided channel.
+// The channel is closed when all records have been sent or an error occurs.
+func (c *Client) FuturesPriceWithChannel(ctx context.Context, req FuturesPriceRequest, ch chan<- FuturesPrice) error {
+	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string, emit func(FuturesPrice) error) (streamedPage[FuturesPrice], error) {
+		params := futuresPriceParameters{FuturesPriceRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/derivatives/bars/daily/futures", params, emit)
+	})
+}
+
+// OpenInterestPoint is the total open interest of one contract month of a futures category on a trading day.
+type OpenInterestPoint struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// ContractMonth is the contract month in YYYY-MM format.
+	ContractMonth string
+	// OpenInterest is the number of outstanding contracts, summed over the contracts of the month.
+	OpenInterest int64
+}
+
+// FuturesOpenInterest retrieves the futures prices of category for every trading day in [from, to] (YYYY-MM-DD)
+// and aggregates them into open-interest time series keyed by ContractMonth, for roll analysis and term
+// structures. Non-trading days are skipped using the trading calendar, and the days are fetched concurrently
+// like [Client.IndexOptionPriceRange]. Each series is sorted by date and has one point per day, summing the
+// OpenInterest of every contract of the category that shares the contract month. If some dates fail, the
+// series built from the remaining dates are returned along with the joined errors.
+func (c *Client) FuturesOpenInterest(ctx context.Context, category, from, to string) (map[string][]OpenInterestPoint, error) {
+	if category == "" {
+		return nil, errors.New("category is required")
+	}
+	entries, err := c.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading calendar: %w", err)
+	}
+	dates := NewCalendar(entries).TradingDays(from, to)
+	byDate, err := fetchBatch(ctx, c, dates, func(ctx context.Context, date string) ([]FuturesPrice, error) {
+		return c.FuturesPrice(ctx, FuturesPriceRequest{Date: date, Category: &category})
+	})
+	return groupOpenInterest(byDate), err
+}
+
+// groupOpenInterest sums the open interest of the daily futures prices per contract month and date, and sorts
+// each month's series by date.
+func groupOpenInterest(byDate map[string][]FuturesPrice) map[string][]OpenInterestPoint {
+	series := make(map[string][]OpenInterestPoint)
+	for date, prices := range byDate {
+		totals := make(map[string]int64)
+		for _, p := range prices {
+			totals[p.ContractMonth] += p.OpenInterest
+		}
+		for month, oi := range totals {
+			series[month] = append(series[month], OpenInterestPoint{Date: date, ContractMonth: month, OpenInterest: oi})
+		}
+	}
+	for _, month := range series {
+		slices.SortFunc(month, func(a, b OpenInterestPoint) int { return cmp.Compare(a.Date, b.Date) })
+	}
+	return series
+}
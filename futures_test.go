@@ -0,0 +1,140 @@
+package jquants
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_FuturesPrice(t *testing.T) {
+	client := setupClient(t)
+	category := "TOPIXF"
+	req := FuturesPriceRequest{Date: "2025-01-06", Category: &category}
+	resp, err := client.FuturesPrice(t.Context(), req)
+	if err != nil {
+		t.Errorf("Failed to get futures price: %v", err)
+	}
+	if len(resp) == 0 {
+		t.Error("Empty response")
+	}
+	for _, p := range resp {
+		if p.DerivativeProductCategory != category {
+			t.Errorf("Unexpected product category: got %s, want %s", p.DerivativeProductCategory, category)
+		}
+	}
+}
+
+func TestClient_FuturesPriceWithChannel(t *testing.T) {
+	client := setupClient(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	req := FuturesPriceRequest{Date: "2025-01-06"}
+	ch := make(chan FuturesPrice)
+	go func() {
+		if e := client.FuturesPriceWithChannel(ctx, req, ch); e != nil {
+			t.Errorf("Failed to get futures price: %v", e)
+		}
+	}()
+	found := false
+	for range ch {
+		found = true
+	}
+	if !found {
+		t.Error("Empty response")
+	}
+}
+
+func TestFuturesPrice_UnmarshalJSON(t *testing.T) {
+	var p FuturesPrice
+	data := `{"Date":"2025-01-06","Code":"161030018","ProdCat":"TOPIXF","O":2750.5,"C":2761,"EO":"","Vo":1234,"OI":56789,"CM":"2025-03","Settle":2760.5,"SQD":"2025-03-14","CCMFlag":"1"}`
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("Failed to unmarshal futures price: %v", err)
+	}
+	if p.WholeDayOpen == nil || *p.WholeDayOpen != "2750.5" || p.SettlementPrice == nil || *p.SettlementPrice != "2760.5" {
+		t.Errorf("Unexpected fractional prices: open=%v, settlement=%v", p.WholeDayOpen, p.SettlementPrice)
+	}
+	if p.NightSessionOpen != nil || p.LastTradingDay != nil {
+		t.Errorf("Expected empty and missing fields to be nil: night open=%v, last trading day=%v", p.NightSessionOpen, p.LastTradingDay)
+	}
+	if p.DerivativeProductCategory != "TOPIXF" || p.Volume != 1234 || p.OpenInterest != 56789 || !p.CentralContractMonthFlag {
+		t.Errorf("Unexpected fields: %+v", p)
+	}
+	if err := json.Unmarshal([]byte(`{"O":true}`), &p); err == nil {
+		t.Error("Expected error for a non-numeric price")
+	}
+}
+
+func TestFuturesPriceParameters_Date(t *testing.T) {
+	transport := &flakyTransport{}
+	client := NewClient(BaseURL, "", WithHTTPClient(&http.Client{Transport: transport}))
+	_, err := client.FuturesPrice(t.Context(), FuturesPriceRequest{})
+	if err == nil || !strings.Contains(err.Error(), "date is required") {
+		t.Errorf("Expected a missing date error, got %v", err)
+	}
+	if _, err := (futuresPriceParameters{FuturesPriceRequest: FuturesPriceRequest{Date: "20250106"}}).values(); err == nil {
+		t.Error("Expected error for a date not in YYYY-MM-DD format")
+	}
+	if transport.calls != 0 {
+		t.Errorf("Expected no HTTP request for an invalid date, got %d", transport.calls)
+	}
+}
+
+func TestFuturesOpenInterest(t *testing.T) {
+	var mu sync.Mutex
+	var dates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/markets/calendar":
+			fmt.Fprint(w, `{"data":[{"Date":"2025-01-03","HolDiv":"0"},{"Date":"2025-01-06","HolDiv":"1"},{"Date":"2025-01-07","HolDiv":"1"}]}`)
+		case "/derivatives/bars/daily/futures":
+			date := r.URL.Query().Get("date")
+			if r.URL.Query().Get("category") != "NK225F" {
+				t.Errorf("Unexpected category: %s", r.URL.RawQuery)
+			}
+			mu.Lock()
+			dates = append(dates, date)
+			mu.Unlock()
+			oi := map[string]int{"2025-01-06": 100, "2025-01-07": 110}[date]
+			fmt.Fprintf(w, `{"data":[
+				{"Date":%[1]q,"Code":"169060019","ProdCat":"NK225F","OI":%[2]d,"CM":"2025-06"},
+				{"Date":%[1]q,"Code":"169030019","ProdCat":"NK225F","OI":%[3]d,"CM":"2025-03"},
+				{"Date":%[1]q,"Code":"169030119","ProdCat":"NK225F","OI":5,"CM":"2025-03"}
+			]}`, date, oi/10, oi)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewTestClient(server.URL, "test", nil)
+	series, err := client.FuturesOpenInterest(t.Context(), "NK225F", "2025-01-03", "2025-01-07")
+	if err != nil {
+		t.Fatalf("Failed to get futures open interest: %v", err)
+	}
+	slices.Sort(dates)
+	if !slices.Equal(dates, []string{"2025-01-06", "2025-01-07"}) {
+		t.Errorf("Expected only trading days to be requested: %v", dates)
+	}
+	if len(series) != 2 {
+		t.Fatalf("Unexpected contract months: %v", series)
+	}
+	march := series["2025-03"]
+	if len(march) != 2 || march[0].Date != "2025-01-06" || march[1].Date != "2025-01-07" {
+		t.Fatalf("Unexpected March series: %+v", march)
+	}
+	if march[0].OpenInterest != 105 || march[1].OpenInterest != 115 || march[0].ContractMonth != "2025-03" {
+		t.Errorf("Expected open interest summed per contract month: %+v", march)
+	}
+	june := series["2025-06"]
+	if len(june) != 2 || june[0].OpenInterest != 10 || june[1].OpenInterest != 11 {
+		t.Errorf("Unexpected June series: %+v", june)
+	}
+	if _, err := client.FuturesOpenInterest(t.Context(), "", "2025-01-03", "2025-01-07"); err == nil {
+		t.Error("Expected an error without a category")
+	}
+}
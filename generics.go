@@ -2,6 +2,8 @@ package jquants
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
 )
 
@@ -24,3 +26,95 @@ type Response[T any] interface {
 	// NextPageKey returns the pagination key for the next page, or nil if there are no more pages
 	NextPageKey() *string
 }
+
+// Do sends req and fetches all of its pages through the client's pagination loop, with the same retries,
+// loop timeout, cache, and rate limiting as the built-in methods. Each page is requested from req.Path() with
+// req.Values(), after the page's key has been set with SetPaginationKey, and its "data" array is decoded into T.
+// It lets callers add endpoints the package does not cover yet:
+//
+//	type announcementRequest struct{ key *string }
+//
+//	func (r *announcementRequest) Path() string { return "/fins/announcement" }
+//	func (r *announcementRequest) Values() (url.Values, error) {
+//		v := url.Values{}
+//		if r.key != nil {
+//			v.Set("pagination_key", *r.key)
+//		}
+//		return v, nil
+//	}
+//	func (r *announcementRequest) SetPaginationKey(key *string) { r.key = key }
+//	func (r *announcementRequest) Send(ctx context.Context, c *jquants.Client) ([]Announcement, error) {
+//		return jquants.Do[Announcement](ctx, c, r)
+//	}
+func Do[T any](ctx context.Context, c *Client, req Request[T]) ([]T, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (pageResponse[T], error) {
+		req.SetPaginationKey(paginationKey)
+		return sendPage(ctx, c, req)
+	})
+}
+
+// pageResponse is the response envelope of a single page decoded by Do.
+type pageResponse[T any] struct {
+	Data          records[T] `json:"data"`
+	PaginationKey *string    `json:"pagination_key"`
+}
+
+func (r pageResponse[T]) Items() []T                      { return r.Data.items }
+func (r pageResponse[T]) NextPageKey() *string            { return r.PaginationKey }
+func (r pageResponse[T]) malformedRecords() []RecordError { return r.Data.errs }
+
+// requestParameters adapts a Request to the parameters interface used by sendRequest.
+type requestParameters[T any] struct{ Request[T] }
+
+func (p requestParameters[T]) values() (url.Values, error) { return p.Values() }
+
+func sendPage[T any](ctx context.Context, c *Client, req Request[T]) (pageResponse[T], error) {
+	var r pageResponse[T]
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, req.Path(), requestParameters[T]{req})
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// CollectN runs a channel-based method such as [Client.StockPriceWithChannel] and collects up to n items
+// into a slice. Once n items have arrived, the context passed to fn is canceled, so the remaining pages are
+// not fetched. A deadline or cancellation of ctx stops collection early; the items received so far are
+// returned together with the error. fn must close ch when it returns, as the *WithChannel methods do.
+//
+//	prices, err := jquants.CollectN(ctx, func(ctx context.Context, ch chan<- jquants.StockPrice) error {
+//		return client.StockPriceWithChannel(ctx, req, ch)
+//	}, 100)
+func CollectN[T any](ctx context.Context, fn func(ctx context.Context, ch chan<- T) error, n int) ([]T, error) {
+	if n <= 0 {
+		return []T{}, nil
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan T)
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx, ch) }()
+	items := make([]T, 0, n)
+	for item := range ch {
+		if len(items) < n {
+			items = append(items, item)
+		}
+		if len(items) == n {
+			cancel()
+		}
+	}
+	err := <-done
+	// The cancellation after the n-th item is not an error unless the caller's context ended as well.
+	if len(items) == n && errors.Is(err, context.Canceled) && parent.Err() == nil {
+		err = nil
+	}
+	return items, err
+}
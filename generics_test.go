@@ -0,0 +1,87 @@
+package jquants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCollectN(t *testing.T) {
+	infinite := func(ctx context.Context, ch chan<- int) error {
+		defer close(ch)
+		for i := 0; ; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	items, err := CollectN(t.Context(), infinite, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 || items[2] != 2 {
+		t.Errorf("Unexpected items: %v", items)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	slow := func(ctx context.Context, ch chan<- int) error {
+		defer close(ch)
+		ch <- 1
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	items, err = CollectN(ctx, slow, 3)
+	if !errors.Is(err, context.DeadlineExceeded) || len(items) != 1 {
+		t.Errorf("Expected partial items with a deadline error, got %v, %v", items, err)
+	}
+}
+
+type testRequest struct {
+	key *string
+}
+
+func (r *testRequest) Path() string { return "/custom" }
+func (r *testRequest) Values() (url.Values, error) {
+	v := url.Values{"code": {"72030"}}
+	if r.key != nil {
+		v.Set("pagination_key", *r.key)
+	}
+	return v, nil
+}
+func (r *testRequest) SetPaginationKey(key *string) { r.key = key }
+func (r *testRequest) Send(ctx context.Context, c *Client) ([]EarningsAnnouncement, error) {
+	return Do[EarningsAnnouncement](ctx, c, r)
+}
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom" || r.URL.Query().Get("code") != "72030" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"unexpected request"}`)
+			return
+		}
+		if r.URL.Query().Get("pagination_key") == "" {
+			fmt.Fprint(w, `{"data":[{"Date":"2025-01-06","Code":"72030"}],"pagination_key":"next"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"Date":"2025-01-07","Code":"72030"}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "test")
+	var req Request[EarningsAnnouncement] = &testRequest{}
+	data, err := req.Send(t.Context(), client)
+	if err != nil {
+		t.Fatalf("Failed to send custom request: %v", err)
+	}
+	if len(data) != 2 || data[0].Date != "2025-01-06" || data[1].Date != "2025-01-07" {
+		t.Errorf("Unexpected data: %+v", data)
+	}
+}
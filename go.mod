@@ -2,4 +2,4 @@ module github.com/s-shiga/jquants-go/v2
 
 go 1.24.2
 
-require golang.org/x/time v0.14.0 // indirect
+require golang.org/x/time v0.14.0
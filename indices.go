@@ -5,7 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
+	"math"
 	"net/url"
+	"slices"
+	"strings"
+	"time"
 )
 
 // IndexPrice represents daily OHLC (Open, High, Low, Close) data for a market index.
@@ -64,6 +69,9 @@ type indexPriceParameters struct {
 }
 
 func (p indexPriceParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.Date != nil {
 		v.Add("date", *p.Date)
@@ -85,38 +93,83 @@ func (p indexPriceParameters) values() (url.Values, error) {
 	return v, nil
 }
 
-type indexPriceResponse struct {
-	Data          []IndexPrice `json:"data"`
-	PaginationKey *string      `json:"pagination_key"`
-}
+var _ Request[IndexPrice] = (*indexPriceParameters)(nil)
 
-func (r indexPriceResponse) Items() []IndexPrice    { return r.Data }
-func (r indexPriceResponse) NextPageKey() *string { return r.PaginationKey }
+func (p *indexPriceParameters) Path() string                 { return "/indices/bars/daily" }
+func (p *indexPriceParameters) Values() (url.Values, error)  { return p.values() }
+func (p *indexPriceParameters) SetPaginationKey(key *string) { p.PaginationKey = key }
 
-func (c *Client) sendIndexPriceRequest(ctx context.Context, params indexPriceParameters) (indexPriceResponse, error) {
-	var r indexPriceResponse
-	resp, err := c.sendRequest(ctx, "/indices/bars/daily", params)
-	if err != nil {
-		return r, fmt.Errorf("failed to send GET request: %w", err)
-	}
-	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
-	}
-	if err = decodeResponse(resp, &r); err != nil {
-		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
-	}
-	return r, nil
+func (p *indexPriceParameters) Send(ctx context.Context, c *Client) ([]IndexPrice, error) {
+	return Do[IndexPrice](ctx, c, p)
 }
 
 // IndexPrice retrieves daily index prices from the /indices/bars/daily endpoint.
 // It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged, like [Client.StockPrice].
 func (c *Client) IndexPrice(ctx context.Context, req IndexPriceRequest) ([]IndexPrice, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (indexPriceResponse, error) {
+	fetch := func(from, to *string) ([]IndexPrice, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return Do[IndexPrice](ctx, c, &indexPriceParameters{IndexPriceRequest: chunk})
+	}
+	if req.Date != nil {
+		return fetch(req.From, req.To)
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
+}
+
+// IndexPriceSeq returns an iterator over daily index prices, decoded one at a time like [Client.StockPriceSeq].
+// Date ranges are not split into chunks.
+func (c *Client) IndexPriceSeq(ctx context.Context, req IndexPriceRequest) iter.Seq2[IndexPrice, error] {
+	return fetchAllPagesSeq(ctx, c, func(ctx context.Context, paginationKey *string, emit func(IndexPrice) error) (streamedPage[IndexPrice], error) {
 		params := indexPriceParameters{IndexPriceRequest: req, PaginationKey: paginationKey}
-		return c.sendIndexPriceRequest(ctx, params)
+		return streamPage(ctx, c, "/indices/bars/daily", params, emit)
 	})
 }
 
+// availableIndicesLookback is how many calendar days back AvailableIndices looks for a trading day with data.
+const availableIndicesLookback = 30
+
+// availableIndicesAttempts is the number of most recent trading days AvailableIndices queries before giving up.
+const availableIndicesAttempts = 5
+
+// AvailableIndices discovers the index codes that currently return data, by querying /indices/bars/daily
+// for the most recent trading day with any records. The codes are sorted and cached for the lifetime of the client,
+// so only the first call makes API requests. Plans whose data is delayed by more than availableIndicesLookback
+// days get an error.
+func (c *Client) AvailableIndices(ctx context.Context) ([]string, error) {
+	c.indicesMu.Lock()
+	defer c.indicesMu.Unlock()
+	if c.availableIndices != nil {
+		return slices.Clone(c.availableIndices), nil
+	}
+	now := time.Now().In(jst)
+	from := now.AddDate(0, 0, -availableIndicesLookback).Format(dateLayout)
+	to := now.Format(dateLayout)
+	entries, err := c.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading calendar: %w", err)
+	}
+	days := NewCalendar(entries).TradingDays(from, to)
+	for i := len(days) - 1; i >= max(0, len(days)-availableIndicesAttempts); i-- {
+		prices, err := c.IndexPrice(ctx, IndexPriceRequest{Date: &days[i]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get index prices: %w", err)
+		}
+		if len(prices) == 0 {
+			continue
+		}
+		codes := make([]string, 0, len(prices))
+		for _, p := range prices {
+			codes = append(codes, p.Code)
+		}
+		slices.Sort(codes)
+		c.availableIndices = slices.Compact(codes)
+		return slices.Clone(c.availableIndices), nil
+	}
+	return nil, errors.New("no index prices found for recent trading days")
+}
+
 // TopixPrice represents daily OHLC (Open, High, Low, Close) data for the TOPIX index.
 type TopixPrice struct {
 	// Date is the trading date in YYYY-MM-DD format.
@@ -164,6 +217,9 @@ type topixPriceParameters struct {
 }
 
 func (p topixPriceParameters) values() (url.Values, error) {
+	if err := checkDateRange(nil, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.From != nil {
 		v.Add("from", *p.From)
@@ -178,23 +234,25 @@ func (p topixPriceParameters) values() (url.Values, error) {
 }
 
 type topixPriceResponse struct {
-	Data          []TopixPrice `json:"data"`
-	PaginationKey *string      `json:"pagination_key"`
+	Data          records[TopixPrice] `json:"data"`
+	PaginationKey *string             `json:"pagination_key"`
 }
 
-func (r topixPriceResponse) Items() []TopixPrice    { return r.Data }
-func (r topixPriceResponse) NextPageKey() *string { return r.PaginationKey }
+func (r topixPriceResponse) Items() []TopixPrice             { return r.Data.items }
+func (r topixPriceResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r topixPriceResponse) malformedRecords() []RecordError { return r.Data.errs }
 
 func (c *Client) sendTopixPriceRequest(ctx context.Context, params topixPriceParameters) (topixPriceResponse, error) {
 	var r topixPriceResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/indices/bars/daily/topix", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r, nil
@@ -202,9 +260,57 @@ func (c *Client) sendTopixPriceRequest(ctx context.Context, params topixPricePar
 
 // TopixPrices retrieves daily TOPIX index prices from the /indices/bars/daily/topix endpoint.
 // It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged, like [Client.StockPrice].
 func (c *Client) TopixPrices(ctx context.Context, req TopixPriceRequest) ([]TopixPrice, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (topixPriceResponse, error) {
-		params := topixPriceParameters{TopixPriceRequest: req, PaginationKey: paginationKey}
-		return c.sendTopixPriceRequest(ctx, params)
-	})
+	fetch := func(from, to *string) ([]TopixPrice, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (topixPriceResponse, error) {
+			params := topixPriceParameters{TopixPriceRequest: chunk, PaginationKey: paginationKey}
+			return c.sendTopixPriceRequest(ctx, params)
+		})
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
+}
+
+// weightSumTolerance is how far the weights passed to SectorContributions may deviate from 1.
+const weightSumTolerance = 1e-3
+
+// SectorContributions computes each sector index's contribution to a benchmark move over a period:
+// its weight times its return from the first to the last close in its series.
+// indexPrices and weights are keyed by index code (e.g. the TOPIX-17 or 33-sector index codes).
+// Series may be unordered. A sector with a weight but no (or fewer than two) usable closes is omitted
+// from the result; sectors without a weight are ignored. It returns an error if the weights do not sum
+// to 1 within weightSumTolerance.
+func SectorContributions(indexPrices map[string][]IndexPrice, weights map[string]float64) (map[string]float64, error) {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	if math.Abs(sum-1) > weightSumTolerance {
+		return nil, fmt.Errorf("weights sum to %g, want 1", sum)
+	}
+	contributions := make(map[string]float64, len(weights))
+	for code, w := range weights {
+		closes := make([]IndexPrice, 0, len(indexPrices[code]))
+		for _, p := range indexPrices[code] {
+			if p.Close != "" {
+				closes = append(closes, p)
+			}
+		}
+		if len(closes) < 2 {
+			continue
+		}
+		slices.SortFunc(closes, func(a, b IndexPrice) int { return strings.Compare(a.Date, b.Date) })
+		first, err := closes[0].Close.Float64()
+		if err != nil || first == 0 {
+			continue
+		}
+		last, err := closes[len(closes)-1].Close.Float64()
+		if err != nil {
+			continue
+		}
+		contributions[code] = w * (last/first - 1)
+	}
+	return contributions, nil
 }
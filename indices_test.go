@@ -1,6 +1,8 @@
 package jquants
 
 import (
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -17,6 +19,17 @@ func TestClient_IndexPrice(t *testing.T) {
 	}
 }
 
+func TestClient_AvailableIndices(t *testing.T) {
+	client := setupClient(t)
+	indices, err := client.AvailableIndices(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to get available indices: %s", err)
+	}
+	if !slices.Contains(indices, "0000") {
+		t.Errorf("Expected TOPIX in available indices: %v", indices)
+	}
+}
+
 func TestClient_TopixPrices(t *testing.T) {
 	client := setupClient(t)
 	res, err := client.TopixPrices(t.Context(), TopixPriceRequest{})
@@ -27,3 +40,20 @@ func TestClient_TopixPrices(t *testing.T) {
 		t.Error("Empty topix price")
 	}
 }
+
+func TestSectorContributions(t *testing.T) {
+	indexPrices := map[string][]IndexPrice{
+		"0040": {{Date: "2024-01-05", Close: "110"}, {Date: "2024-01-04", Close: "100"}},
+		"0041": {{Date: "2024-01-04", Close: "200"}, {Date: "2024-01-05", Close: "190"}},
+	}
+	got, err := SectorContributions(indexPrices, map[string]float64{"0040": 0.5, "0041": 0.3, "0042": 0.2})
+	if err != nil {
+		t.Fatalf("Failed to compute contributions: %v", err)
+	}
+	if len(got) != 2 || math.Abs(got["0040"]-0.05) > 1e-9 || math.Abs(got["0041"]+0.015) > 1e-9 {
+		t.Errorf("Unexpected contributions: %v", got)
+	}
+	if _, err := SectorContributions(indexPrices, map[string]float64{"0040": 0.5}); err == nil {
+		t.Error("Expected error for weights not summing to 1")
+	}
+}
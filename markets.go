@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
+	"slices"
 	"strconv"
+	"time"
+
+	"github.com/s-shiga/jquants-go/v2/codes"
 )
 
 // MarginTradingOutstanding represents margin trading balance data for a security.
@@ -34,33 +39,36 @@ type MarginTradingOutstanding struct {
 
 func (mtv *MarginTradingOutstanding) UnmarshalJSON(b []byte) error {
 	var raw struct {
-		Date                               string  `json:"Date"`
-		Code                               string  `json:"Code"`
-		ShortMarginTradeVolume             float64 `json:"ShrtVol"`
-		LongMarginTradeVolume              float64 `json:"LongVol"`
-		ShortNegotiableMarginTradeVolume   float64 `json:"ShrtNegVol"`
-		LongNegotiableMarginTradeVolume    float64 `json:"LongNegVol"`
-		ShortStandardizedMarginTradeVolume float64 `json:"ShrtStdVol"`
-		LongStandardizedMarginTradeVolume  float64 `json:"LongStdVol"`
-		IssueType                          string  `json:"IssType"`
+		Date                               string      `json:"Date"`
+		Code                               string      `json:"Code"`
+		ShortMarginTradeVolume             json.Number `json:"ShrtVol"`
+		LongMarginTradeVolume              json.Number `json:"LongVol"`
+		ShortNegotiableMarginTradeVolume   json.Number `json:"ShrtNegVol"`
+		LongNegotiableMarginTradeVolume    json.Number `json:"LongNegVol"`
+		ShortStandardizedMarginTradeVolume json.Number `json:"ShrtStdVol"`
+		LongStandardizedMarginTradeVolume  json.Number `json:"LongStdVol"`
+		IssueType                          string      `json:"IssType"`
 	}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return fmt.Errorf("failed to unmarshal margin trading outstanding: %w", err)
 	}
-	var err error
 	mtv.Date = raw.Date
 	issueType, err := strconv.ParseInt(raw.IssueType, 10, 8)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal margin trading outstanding: %w", err)
 	}
+	u := &unmarshaler{}
 	mtv.Code = raw.Code
-	mtv.TotalShortBalance = int64(raw.ShortMarginTradeVolume)
-	mtv.TotalLongBalance = int64(raw.LongMarginTradeVolume)
-	mtv.ShortNegotiableBalance = int64(raw.ShortNegotiableMarginTradeVolume)
-	mtv.LongNegotiableBalance = int64(raw.LongNegotiableMarginTradeVolume)
-	mtv.ShortStandardizedBalance = int64(raw.ShortStandardizedMarginTradeVolume)
-	mtv.LongStandardizedBalance = int64(raw.LongStandardizedMarginTradeVolume)
+	mtv.TotalShortBalance = u.integer(raw.ShortMarginTradeVolume)
+	mtv.TotalLongBalance = u.integer(raw.LongMarginTradeVolume)
+	mtv.ShortNegotiableBalance = u.integer(raw.ShortNegotiableMarginTradeVolume)
+	mtv.LongNegotiableBalance = u.integer(raw.LongNegotiableMarginTradeVolume)
+	mtv.ShortStandardizedBalance = u.integer(raw.ShortStandardizedMarginTradeVolume)
+	mtv.LongStandardizedBalance = u.integer(raw.LongStandardizedMarginTradeVolume)
 	mtv.IssueType = int8(issueType)
+	if u.err != nil {
+		return fmt.Errorf("failed to unmarshal margin trading outstanding: %w", u.err)
+	}
 	return nil
 }
 
@@ -83,6 +91,9 @@ type marginTradingOutstandingParameters struct {
 }
 
 func (p marginTradingOutstandingParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.Date != nil {
 		v.Add("date", *p.Date)
@@ -90,7 +101,11 @@ func (p marginTradingOutstandingParameters) values() (url.Values, error) {
 		if p.Code == nil {
 			return nil, errors.New("code or date is required")
 		}
-		v.Add("code", *p.Code)
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
 		if p.From != nil {
 			v.Add("from", *p.From)
 		}
@@ -105,23 +120,25 @@ func (p marginTradingOutstandingParameters) values() (url.Values, error) {
 }
 
 type marginTradingOutstandingResponse struct {
-	Data          []MarginTradingOutstanding `json:"data"`
-	PaginationKey *string                    `json:"pagination_key"`
+	Data          records[MarginTradingOutstanding] `json:"data"`
+	PaginationKey *string                           `json:"pagination_key"`
 }
 
-func (r marginTradingOutstandingResponse) Items() []MarginTradingOutstanding { return r.Data }
+func (r marginTradingOutstandingResponse) Items() []MarginTradingOutstanding { return r.Data.items }
 func (r marginTradingOutstandingResponse) NextPageKey() *string              { return r.PaginationKey }
+func (r marginTradingOutstandingResponse) malformedRecords() []RecordError   { return r.Data.errs }
 
 func (c *Client) sendMarginTradingOutstandingRequest(ctx context.Context, params marginTradingOutstandingParameters) (marginTradingOutstandingResponse, error) {
 	var r marginTradingOutstandingResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/markets/margin-interest", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r, nil
@@ -129,12 +146,21 @@ func (c *Client) sendMarginTradingOutstandingRequest(ctx context.Context, params
 
 // MarginTradingOutstanding retrieves margin trading balance data from the /markets/margin-interest endpoint.
 // It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged, like [Client.StockPrice].
 // See https://jpx-jquants.com/en/spec/mkt-margin-int for API details.
 func (c *Client) MarginTradingOutstanding(ctx context.Context, req MarginTradingOutstandingRequest) ([]MarginTradingOutstanding, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (marginTradingOutstandingResponse, error) {
-		params := marginTradingOutstandingParameters{MarginTradingOutstandingRequest: req, PaginationKey: paginationKey}
-		return c.sendMarginTradingOutstandingRequest(ctx, params)
-	})
+	fetch := func(from, to *string) ([]MarginTradingOutstanding, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (marginTradingOutstandingResponse, error) {
+			params := marginTradingOutstandingParameters{MarginTradingOutstandingRequest: chunk, PaginationKey: paginationKey}
+			return c.sendMarginTradingOutstandingRequest(ctx, params)
+		})
+	}
+	if req.Date != nil {
+		return fetch(req.From, req.To)
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
 }
 
 // ShortSellingValue represents short selling turnover data by sector.
@@ -154,20 +180,24 @@ type ShortSellingValue struct {
 
 func (sst *ShortSellingValue) UnmarshalJSON(b []byte) error {
 	var raw struct {
-		Date                                         string  `json:"Date"`
-		Sector33Code                                 string  `json:"S33"`
-		SellingExcludingShortSellingTurnoverValue    float64 `json:"SellExShortVa"`
-		ShortSellingWithRestrictionsTurnoverValue    float64 `json:"ShrtWithResVa"`
-		ShortSellingWithoutRestrictionsTurnoverValue float64 `json:"ShrtNoResVa"`
+		Date                                         string      `json:"Date"`
+		Sector33Code                                 string      `json:"S33"`
+		SellingExcludingShortSellingTurnoverValue    json.Number `json:"SellExShortVa"`
+		ShortSellingWithRestrictionsTurnoverValue    json.Number `json:"ShrtWithResVa"`
+		ShortSellingWithoutRestrictionsTurnoverValue json.Number `json:"ShrtNoResVa"`
 	}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return fmt.Errorf("failed to unmarshal short selling value: %w", err)
 	}
+	u := &unmarshaler{}
 	sst.Date = raw.Date
 	sst.Sector33Code = raw.Sector33Code
-	sst.LongSellingValue = int64(raw.SellingExcludingShortSellingTurnoverValue)
-	sst.ShortSellingWithRestrictions = int64(raw.ShortSellingWithRestrictionsTurnoverValue)
-	sst.ShortSellingWithoutRestrictions = int64(raw.ShortSellingWithoutRestrictionsTurnoverValue)
+	sst.LongSellingValue = u.integer(raw.SellingExcludingShortSellingTurnoverValue)
+	sst.ShortSellingWithRestrictions = u.integer(raw.ShortSellingWithRestrictionsTurnoverValue)
+	sst.ShortSellingWithoutRestrictions = u.integer(raw.ShortSellingWithoutRestrictionsTurnoverValue)
+	if u.err != nil {
+		return fmt.Errorf("failed to unmarshal short selling value: %w", u.err)
+	}
 	return nil
 }
 
@@ -190,6 +220,9 @@ type shortSellingValueParameters struct {
 }
 
 func (p shortSellingValueParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.Sector33Code != nil {
 		v.Add("s33", *p.Sector33Code)
@@ -216,23 +249,25 @@ func (p shortSellingValueParameters) values() (url.Values, error) {
 }
 
 type shortSellingValueResponse struct {
-	Data          []ShortSellingValue `json:"data"`
-	PaginationKey *string             `json:"pagination_key"`
+	Data          records[ShortSellingValue] `json:"data"`
+	PaginationKey *string                    `json:"pagination_key"`
 }
 
-func (r shortSellingValueResponse) Items() []ShortSellingValue { return r.Data }
-func (r shortSellingValueResponse) NextPageKey() *string       { return r.PaginationKey }
+func (r shortSellingValueResponse) Items() []ShortSellingValue      { return r.Data.items }
+func (r shortSellingValueResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r shortSellingValueResponse) malformedRecords() []RecordError { return r.Data.errs }
 
 func (c *Client) sendShortSellingValueRequest(ctx context.Context, params shortSellingValueParameters) (shortSellingValueResponse, error) {
 	var r shortSellingValueResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/markets/short-ratio", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r, nil
@@ -240,18 +275,410 @@ func (c *Client) sendShortSellingValueRequest(ctx context.Context, params shortS
 
 // ShortSellingValue retrieves short selling turnover data from the /markets/short-ratio endpoint.
 // It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged, like [Client.StockPrice].
 func (c *Client) ShortSellingValue(ctx context.Context, req ShortSellingValueRequest) ([]ShortSellingValue, error) {
-	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (shortSellingValueResponse, error) {
-		params := shortSellingValueParameters{ShortSellingValueRequest: req, PaginationKey: paginationKey}
-		return c.sendShortSellingValueRequest(ctx, params)
-	})
+	fetch := func(from, to *string) ([]ShortSellingValue, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (shortSellingValueResponse, error) {
+			params := shortSellingValueParameters{ShortSellingValueRequest: chunk, PaginationKey: paginationKey}
+			return c.sendShortSellingValueRequest(ctx, params)
+		})
+	}
+	if req.Sector33Code == nil || req.Date != nil {
+		return fetch(req.From, req.To)
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
+}
+
+// MarketShortRatio aggregates per-sector short selling values into the market-wide short ratio:
+// (short selling with restrictions + without restrictions) / total turnover, where total turnover also
+// includes long selling. It returns 0 for empty input or a zero total.
+// All values are summed regardless of date; use MarketShortRatioByDate for a time series.
+func MarketShortRatio(values []ShortSellingValue) float64 {
+	var short, total int64
+	for _, v := range values {
+		s := v.ShortSellingWithRestrictions + v.ShortSellingWithoutRestrictions
+		short += s
+		total += s + v.LongSellingValue
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(short) / float64(total)
+}
+
+// MarketShortRatioByDate computes MarketShortRatio separately for each date, keyed by date (YYYY-MM-DD).
+func MarketShortRatioByDate(values []ShortSellingValue) map[string]float64 {
+	byDate := make(map[string][]ShortSellingValue)
+	for _, v := range values {
+		byDate[v.Date] = append(byDate[v.Date], v)
+	}
+	ratios := make(map[string]float64, len(byDate))
+	for date, vs := range byDate {
+		ratios[date] = MarketShortRatio(vs)
+	}
+	return ratios
+}
+
+// ShortPressureSeries computes, for each 33-sector code, the daily short pressure: short selling turnover
+// (with and without restrictions) divided by total turnover. Series are keyed by sector code and aligned on the
+// sorted dates present in values, so series[sector][i] belongs to the i-th date for every sector and the series
+// can be charted against one shared date axis. Dates on which a sector has no data or zero turnover are NaN.
+// Several values for the same sector and date are summed.
+func ShortPressureSeries(values []ShortSellingValue) map[string][]float64 {
+	type key struct{ sector, date string }
+	type turnover struct{ short, total int64 }
+	sums := make(map[key]turnover)
+	var dates []string
+	sectors := make(map[string]bool)
+	for _, v := range values {
+		k := key{v.Sector33Code, v.Date}
+		short := v.ShortSellingWithRestrictions + v.ShortSellingWithoutRestrictions
+		t := sums[k]
+		t.short += short
+		t.total += short + v.LongSellingValue
+		sums[k] = t
+		dates = append(dates, v.Date)
+		sectors[v.Sector33Code] = true
+	}
+	slices.Sort(dates)
+	dates = slices.Compact(dates)
+	series := make(map[string][]float64, len(sectors))
+	for sector := range sectors {
+		s := make([]float64, len(dates))
+		for i, date := range dates {
+			s[i] = math.NaN()
+			if t, ok := sums[key{sector, date}]; ok && t.total != 0 {
+				s[i] = float64(t.short) / float64(t.total)
+			}
+		}
+		series[sector] = s
+	}
+	return series
+}
+
+// ShortSellingPosition is a reported short position of 0.5% or more of a company's shares outstanding,
+// which short sellers must disclose under FSA rules.
+type ShortSellingPosition struct {
+	// DisclosedDate is the date the report was published in YYYY-MM-DD format.
+	DisclosedDate string
+	// CalculatedDate is the date on which the position was calculated in YYYY-MM-DD format.
+	CalculatedDate string
+	// Code is the security code (ticker symbol).
+	Code string
+	// ShortSellerName is the name of the short seller.
+	ShortSellerName string
+	// ShortSellerAddress is the address of the short seller.
+	ShortSellerAddress string
+	// DiscretionaryInvestmentContractorName is the name of the discretionary investment contractor, if any.
+	DiscretionaryInvestmentContractorName string
+	// ShortPositionsToSharesOutstandingRatio is the short position as a ratio of shares outstanding
+	// (e.g., 0.0052 for 0.52%; nil if not reported).
+	ShortPositionsToSharesOutstandingRatio *json.Number
+	// ShortPositionsInSharesNumber is the short position in shares.
+	ShortPositionsInSharesNumber int64
+	// PreviousReportingDate is the calculation date of the previous report in YYYY-MM-DD format (nil if none).
+	PreviousReportingDate *string
+	// ShortPositionsInPreviousReportingRatio is the ratio in the previous report (nil if none).
+	ShortPositionsInPreviousReportingRatio *json.Number
 }
 
-// Outstanding Short Selling Positions Reported not implemented
+func (ssp *ShortSellingPosition) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		DisclosedDate                          string      `json:"DiscDate"`
+		CalculatedDate                         string      `json:"CalcDate"`
+		Code                                   string      `json:"Code"`
+		ShortSellerName                        string      `json:"SSName"`
+		ShortSellerAddress                     string      `json:"SSAddr"`
+		DiscretionaryInvestmentContractorName  string      `json:"DICName"`
+		ShortPositionsToSharesOutstandingRatio interface{} `json:"ShrtPosToSO"`
+		ShortPositionsInSharesNumber           json.Number `json:"ShrtPosShares"`
+		PreviousReportingDate                  string      `json:"PrevRptDate"`
+		ShortPositionsInPreviousReportingRatio interface{} `json:"PrevRptRatio"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal short selling position: %w", err)
+	}
+
+	u := &unmarshaler{}
+
+	ssp.DisclosedDate = raw.DisclosedDate
+	ssp.CalculatedDate = raw.CalculatedDate
+	ssp.Code = raw.Code
+	ssp.ShortSellerName = raw.ShortSellerName
+	ssp.ShortSellerAddress = raw.ShortSellerAddress
+	ssp.DiscretionaryInvestmentContractorName = raw.DiscretionaryInvestmentContractorName
+	ssp.ShortPositionsToSharesOutstandingRatio = u.number(raw.ShortPositionsToSharesOutstandingRatio)
+	ssp.ShortPositionsInSharesNumber = u.integer(raw.ShortPositionsInSharesNumber)
+	ssp.PreviousReportingDate = unmarshalTime(raw.PreviousReportingDate)
+	ssp.ShortPositionsInPreviousReportingRatio = u.number(raw.ShortPositionsInPreviousReportingRatio)
+
+	if u.err != nil {
+		return fmt.Errorf("failed to unmarshal short selling position: %w", u.err)
+	}
+	return nil
+}
+
+// ShortSellingPositionsRequest specifies filter parameters for the ShortSellingPositions API.
+// At least one of Code, DisclosedDate, or CalculatedDate must be provided.
+type ShortSellingPositionsRequest struct {
+	// Code filters by security code.
+	Code *string
+	// DisclosedDate filters by publication date in YYYY-MM-DD format.
+	DisclosedDate *string
+	// CalculatedDate filters by calculation date in YYYY-MM-DD format.
+	CalculatedDate *string
+}
+
+type shortSellingPositionsParameters struct {
+	ShortSellingPositionsRequest
+	PaginationKey *string
+}
+
+func (p shortSellingPositionsParameters) values() (url.Values, error) {
+	if err := errors.Join(checkDate("disclosed_date", p.DisclosedDate), checkDate("calculated_date", p.CalculatedDate)); err != nil {
+		return nil, err
+	}
+	if p.Code == nil && p.DisclosedDate == nil && p.CalculatedDate == nil {
+		return nil, errors.New("code, disclosed date, or calculated date is required")
+	}
+	v := url.Values{}
+	if p.Code != nil {
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
+	}
+	if p.DisclosedDate != nil {
+		v.Add("disclosed_date", *p.DisclosedDate)
+	}
+	if p.CalculatedDate != nil {
+		v.Add("calculated_date", *p.CalculatedDate)
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type shortSellingPositionsResponse struct {
+	Data          records[ShortSellingPosition] `json:"data"`
+	PaginationKey *string                       `json:"pagination_key"`
+}
+
+func (r shortSellingPositionsResponse) Items() []ShortSellingPosition   { return r.Data.items }
+func (r shortSellingPositionsResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r shortSellingPositionsResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendShortSellingPositionsRequest(ctx context.Context, params shortSellingPositionsParameters) (shortSellingPositionsResponse, error) {
+	var r shortSellingPositionsResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/markets/short-selling-positions", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// ShortSellingPositions retrieves reported outstanding short selling positions from the
+// /markets/short-selling-positions endpoint. It automatically handles pagination to fetch all matching records.
+func (c *Client) ShortSellingPositions(ctx context.Context, req ShortSellingPositionsRequest) ([]ShortSellingPosition, error) {
+	return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (shortSellingPositionsResponse, error) {
+		params := shortSellingPositionsParameters{ShortSellingPositionsRequest: req, PaginationKey: paginationKey}
+		return c.sendShortSellingPositionsRequest(ctx, params)
+	})
+}
 
 // Margin Trading Outstanding not implemented
 
-// Breakdown Trading not implemented
+// Breakdown represents the daily trading value and volume of a security broken down by trade type:
+// long and short selling, new and closing margin positions, and long buying.
+type Breakdown struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// Code is the security code (ticker symbol).
+	Code string
+	// LongSellValue is the trading value of long selling (excluding margin closing sales) in yen.
+	LongSellValue int64
+	// ShortSellWithoutMarginValue is the trading value of short selling other than new margin sales in yen.
+	ShortSellWithoutMarginValue int64
+	// MarginSellNewValue is the trading value of sales opening new margin positions in yen.
+	MarginSellNewValue int64
+	// MarginSellCloseValue is the trading value of sales closing margin positions in yen.
+	MarginSellCloseValue int64
+	// LongBuyValue is the trading value of long buying (excluding margin buying) in yen.
+	LongBuyValue int64
+	// MarginBuyNewValue is the trading value of purchases opening new margin positions in yen.
+	MarginBuyNewValue int64
+	// MarginBuyCloseValue is the trading value of purchases closing margin positions in yen.
+	MarginBuyCloseValue int64
+	// LongSellVolume is the volume of long selling in shares.
+	LongSellVolume int64
+	// ShortSellWithoutMarginVolume is the volume of short selling other than new margin sales in shares.
+	ShortSellWithoutMarginVolume int64
+	// MarginSellNewVolume is the volume of sales opening new margin positions in shares.
+	MarginSellNewVolume int64
+	// MarginSellCloseVolume is the volume of sales closing margin positions in shares.
+	MarginSellCloseVolume int64
+	// LongBuyVolume is the volume of long buying in shares.
+	LongBuyVolume int64
+	// MarginBuyNewVolume is the volume of purchases opening new margin positions in shares.
+	MarginBuyNewVolume int64
+	// MarginBuyCloseVolume is the volume of purchases closing margin positions in shares.
+	MarginBuyCloseVolume int64
+}
+
+func (bd *Breakdown) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Date                         string      `json:"Date"`
+		Code                         string      `json:"Code"`
+		LongSellValue                json.Number `json:"LongSellVa"`
+		ShortSellWithoutMarginValue  json.Number `json:"ShrtNoMrgnVa"`
+		MarginSellNewValue           json.Number `json:"MrgnSellNewVa"`
+		MarginSellCloseValue         json.Number `json:"MrgnSellCloseVa"`
+		LongBuyValue                 json.Number `json:"LongBuyVa"`
+		MarginBuyNewValue            json.Number `json:"MrgnBuyNewVa"`
+		MarginBuyCloseValue          json.Number `json:"MrgnBuyCloseVa"`
+		LongSellVolume               json.Number `json:"LongSellVo"`
+		ShortSellWithoutMarginVolume json.Number `json:"ShrtNoMrgnVo"`
+		MarginSellNewVolume          json.Number `json:"MrgnSellNewVo"`
+		MarginSellCloseVolume        json.Number `json:"MrgnSellCloseVo"`
+		LongBuyVolume                json.Number `json:"LongBuyVo"`
+		MarginBuyNewVolume           json.Number `json:"MrgnBuyNewVo"`
+		MarginBuyCloseVolume         json.Number `json:"MrgnBuyCloseVo"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal breakdown: %w", err)
+	}
+	u := &unmarshaler{}
+	bd.Date = raw.Date
+	bd.Code = raw.Code
+	bd.LongSellValue = u.integer(raw.LongSellValue)
+	bd.ShortSellWithoutMarginValue = u.integer(raw.ShortSellWithoutMarginValue)
+	bd.MarginSellNewValue = u.integer(raw.MarginSellNewValue)
+	bd.MarginSellCloseValue = u.integer(raw.MarginSellCloseValue)
+	bd.LongBuyValue = u.integer(raw.LongBuyValue)
+	bd.MarginBuyNewValue = u.integer(raw.MarginBuyNewValue)
+	bd.MarginBuyCloseValue = u.integer(raw.MarginBuyCloseValue)
+	bd.LongSellVolume = u.integer(raw.LongSellVolume)
+	bd.ShortSellWithoutMarginVolume = u.integer(raw.ShortSellWithoutMarginVolume)
+	bd.MarginSellNewVolume = u.integer(raw.MarginSellNewVolume)
+	bd.MarginSellCloseVolume = u.integer(raw.MarginSellCloseVolume)
+	bd.LongBuyVolume = u.integer(raw.LongBuyVolume)
+	bd.MarginBuyNewVolume = u.integer(raw.MarginBuyNewVolume)
+	bd.MarginBuyCloseVolume = u.integer(raw.MarginBuyCloseVolume)
+	if u.err != nil {
+		return fmt.Errorf("failed to unmarshal breakdown: %w", u.err)
+	}
+	return nil
+}
+
+// BreakdownRequest specifies filter parameters for the Breakdown API.
+// Either Code or Date must be provided.
+type BreakdownRequest struct {
+	// Code filters by security code. Required if Date is not specified.
+	Code *string
+	// Date filters by a specific date in YYYY-MM-DD format. If specified, Code is ignored.
+	Date *string
+	// From specifies the start date for a date range query (used with Code).
+	From *string
+	// To specifies the end date for a date range query (used with Code).
+	To *string
+}
+
+type breakdownParameters struct {
+	BreakdownRequest
+	PaginationKey *string
+}
+
+func (p breakdownParameters) values() (url.Values, error) {
+	if err := checkDateRange(p.Date, p.From, p.To); err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	if p.Date != nil {
+		v.Add("date", *p.Date)
+	} else {
+		if p.Code == nil {
+			return nil, errors.New("code or date is required")
+		}
+		code, err := codes.NormalizeCode(*p.Code)
+		if err != nil {
+			return nil, err
+		}
+		v.Add("code", code)
+		if p.From != nil {
+			v.Add("from", *p.From)
+		}
+		if p.To != nil {
+			v.Add("to", *p.To)
+		}
+	}
+	if p.PaginationKey != nil {
+		v.Add("pagination_key", *p.PaginationKey)
+	}
+	return v, nil
+}
+
+type breakdownResponse struct {
+	Data          records[Breakdown] `json:"data"`
+	PaginationKey *string            `json:"pagination_key"`
+}
+
+func (r breakdownResponse) Items() []Breakdown              { return r.Data.items }
+func (r breakdownResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r breakdownResponse) malformedRecords() []RecordError { return r.Data.errs }
+
+func (c *Client) sendBreakdownRequest(ctx context.Context, params breakdownParameters) (breakdownResponse, error) {
+	var r breakdownResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
+	resp, err := c.sendRequest(ctx, "/markets/breakdown", params)
+	if err != nil {
+		return r, fmt.Errorf("failed to send GET request: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return r, c.handleErrorResponse(resp)
+	}
+	if err = c.decodeResponse(resp, &r); err != nil {
+		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
+	}
+	return r, nil
+}
+
+// Breakdown retrieves trading breakdown data from the /markets/breakdown endpoint.
+// It automatically handles pagination to fetch all matching records.
+// Date ranges wider than one year are split into several requests and merged, like [Client.StockPrice].
+func (c *Client) Breakdown(ctx context.Context, req BreakdownRequest) ([]Breakdown, error) {
+	fetch := func(from, to *string) ([]Breakdown, error) {
+		chunk := req
+		chunk.From, chunk.To = from, to
+		return fetchAllPages(ctx, c, func(ctx context.Context, paginationKey *string) (breakdownResponse, error) {
+			params := breakdownParameters{BreakdownRequest: chunk, PaginationKey: paginationKey}
+			return c.sendBreakdownRequest(ctx, params)
+		})
+	}
+	if req.Date != nil {
+		return fetch(req.From, req.To)
+	}
+	return fetchDateRangeInChunks(req.From, req.To, maxRangeDays, fetch)
+}
+
+// BreakdownWithChannel retrieves trading breakdown data and streams each record to the provided channel.
+// The channel is closed when all records have been sent or an error occurs.
+func (c *Client) BreakdownWithChannel(ctx context.Context, req BreakdownRequest, ch chan<- Breakdown) error {
+	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string, emit func(Breakdown) error) (streamedPage[Breakdown], error) {
+		params := breakdownParameters{BreakdownRequest: req, PaginationKey: paginationKey}
+		return streamPage(ctx, c, "/markets/breakdown", params, emit)
+	})
+}
 
 // TradingCalendar represents a trading calendar entry indicating whether a date is a trading day.
 type TradingCalendar struct {
@@ -278,6 +705,37 @@ func (tc *TradingCalendar) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// IsTradingDay reports whether the TSE equity market is open on this date (full or half-day session).
+func (tc TradingCalendar) IsTradingDay() bool {
+	return tc.DayType == 1 || tc.DayType == 2
+}
+
+// closingHoursExtensionDate is the first trading day on which the TSE afternoon session closes at 15:30 instead of 15:00.
+const closingHoursExtensionDate = "2024-11-05"
+
+// HalfDayCloseTime returns the TSE equity closing time for this date in JST: 11:30 on half-days,
+// and 15:00 (15:30 from 2024-11-05 onwards) on full trading days. It returns nil on non-trading days
+// or if Date cannot be parsed.
+func (tc TradingCalendar) HalfDayCloseTime() *time.Time {
+	if !tc.IsTradingDay() {
+		return nil
+	}
+	date, err := tc.ParsedDate()
+	if err != nil {
+		return nil
+	}
+	var closeTime time.Time
+	switch {
+	case tc.DayType == 2:
+		closeTime = date.Add(11*time.Hour + 30*time.Minute)
+	case tc.Date >= closingHoursExtensionDate:
+		closeTime = date.Add(15*time.Hour + 30*time.Minute)
+	default:
+		closeTime = date.Add(15 * time.Hour)
+	}
+	return &closeTime
+}
+
 // TradingCalendarRequest specifies filter parameters for the TradingCalendar API.
 type TradingCalendarRequest struct {
 	// HolidayDivision filters by day type (0: holiday, 1: trading day, 2: half-day, 3: non-trading day).
@@ -293,6 +751,9 @@ type tradingCalendarParameters struct {
 }
 
 func (p tradingCalendarParameters) values() (url.Values, error) {
+	if err := checkDateRange(nil, p.From, p.To); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	if p.HolidayDivision != nil {
 		v.Add("hol_div", strconv.Itoa(int(*p.HolidayDivision)))
@@ -319,9 +780,9 @@ func (c *Client) TradingCalendar(ctx context.Context, req TradingCalendarRequest
 		return nil, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return nil, handleErrorResponse(resp)
+		return nil, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return nil, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
 	return r.Data, nil
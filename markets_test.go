@@ -1,7 +1,10 @@
 package jquants
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/s-shiga/jquants-go/v2/codes"
 )
@@ -42,3 +45,134 @@ func TestClient_TradingCalendar(t *testing.T) {
 		t.Errorf("Empty trading calendar")
 	}
 }
+
+func TestTradingCalendar_HalfDayCloseTime(t *testing.T) {
+	tests := []struct {
+		day  TradingCalendar
+		want string
+	}{
+		{TradingCalendar{Date: "2024-11-01", DayType: 1}, "2024-11-01T15:00:00+09:00"},
+		{TradingCalendar{Date: "2024-11-05", DayType: 1}, "2024-11-05T15:30:00+09:00"},
+		{TradingCalendar{Date: "2024-12-30", DayType: 2}, "2024-12-30T11:30:00+09:00"},
+		{TradingCalendar{Date: "2025-01-01", DayType: 0}, ""},
+	}
+	for _, tt := range tests {
+		got := tt.day.HalfDayCloseTime()
+		switch {
+		case tt.want == "" && got != nil:
+			t.Errorf("%s: expected nil, got %s", tt.day.Date, got)
+		case tt.want != "" && (got == nil || got.Format(time.RFC3339) != tt.want):
+			t.Errorf("%s: got %v, want %s", tt.day.Date, got, tt.want)
+		}
+	}
+}
+
+func TestMarketShortRatio(t *testing.T) {
+	values := []ShortSellingValue{
+		{Date: "2024-01-04", LongSellingValue: 60, ShortSellingWithRestrictions: 30, ShortSellingWithoutRestrictions: 10},
+		{Date: "2024-01-04", LongSellingValue: 40, ShortSellingWithRestrictions: 15, ShortSellingWithoutRestrictions: 5},
+		{Date: "2024-01-05", LongSellingValue: 50, ShortSellingWithRestrictions: 50},
+	}
+	if got := MarketShortRatio(values[:2]); got != 0.375 {
+		t.Errorf("Unexpected market short ratio: %v", got)
+	}
+	if got := MarketShortRatio(nil); got != 0 {
+		t.Errorf("Expected 0 for empty input, got %v", got)
+	}
+	byDate := MarketShortRatioByDate(values)
+	if len(byDate) != 2 || byDate["2024-01-04"] != 0.375 || byDate["2024-01-05"] != 0.5 {
+		t.Errorf("Unexpected market short ratios by date: %v", byDate)
+	}
+}
+
+func TestShortPressureSeries(t *testing.T) {
+	values := []ShortSellingValue{
+		{Date: "2024-01-05", Sector33Code: "0050", LongSellingValue: 50, ShortSellingWithRestrictions: 50},
+		{Date: "2024-01-04", Sector33Code: "0050", LongSellingValue: 60, ShortSellingWithRestrictions: 30, ShortSellingWithoutRestrictions: 10},
+		{Date: "2024-01-05", Sector33Code: "1050", LongSellingValue: 75, ShortSellingWithoutRestrictions: 25},
+	}
+	series := ShortPressureSeries(values)
+	if len(series) != 2 {
+		t.Fatalf("Unexpected number of sectors: %v", series)
+	}
+	if got := series["0050"]; len(got) != 2 || got[0] != 0.4 || got[1] != 0.5 {
+		t.Errorf("Unexpected series for 0050: %v", got)
+	}
+	if got := series["1050"]; len(got) != 2 || !math.IsNaN(got[0]) || got[1] != 0.25 {
+		t.Errorf("Unexpected sparse series for 1050: %v", got)
+	}
+}
+
+func TestClient_Breakdown(t *testing.T) {
+	code := "72030"
+	client := setupClient(t)
+	res, err := client.Breakdown(t.Context(), BreakdownRequest{Code: &code})
+	if err != nil {
+		t.Errorf("Failed to get breakdown: %s", err)
+	}
+	if len(res) == 0 {
+		t.Error("Empty breakdown")
+	}
+}
+
+func TestBreakdownParameters(t *testing.T) {
+	if _, err := (breakdownParameters{}).values(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+}
+
+func TestShortSellingPosition_UnmarshalJSON(t *testing.T) {
+	data := `{"DiscDate":"2024-01-10","CalcDate":"2024-01-09","Code":"72030","SSName":"Example Capital","ShrtPosToSO":0.0052,"ShrtPosShares":1234567,"PrevRptDate":"","PrevRptRatio":""}`
+	var p ShortSellingPosition
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("Failed to unmarshal short selling position: %v", err)
+	}
+	if p.ShortPositionsToSharesOutstandingRatio == nil || *p.ShortPositionsToSharesOutstandingRatio != "0.0052" || p.ShortPositionsInSharesNumber != 1234567 {
+		t.Errorf("Unexpected position: %+v", p)
+	}
+	if p.PreviousReportingDate != nil || p.ShortPositionsInPreviousReportingRatio != nil {
+		t.Errorf("Expected empty previous report fields to be nil: %+v", p)
+	}
+	if _, err := (shortSellingPositionsParameters{}).values(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+}
+
+func TestUnmarshalJSON_LargeIntegers(t *testing.T) {
+	const exact = 9007199254740993 // 2^53 + 1, not representable as float64
+	var value ShortSellingValue
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","S33":"0050","SellExShortVa":9007199254740993,"ShrtWithResVa":"9007199254740993","ShrtNoResVa":0}`), &value); err != nil {
+		t.Fatalf("Failed to unmarshal short selling value: %v", err)
+	}
+	if value.LongSellingValue != exact || value.ShortSellingWithRestrictions != exact {
+		t.Errorf("Precision lost: long=%d, restricted=%d", value.LongSellingValue, value.ShortSellingWithRestrictions)
+	}
+	var breakdown Breakdown
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","Code":"72030","LongSellVa":9007199254740993,"LongBuyVo":"1200.0"}`), &breakdown); err != nil {
+		t.Fatalf("Failed to unmarshal breakdown: %v", err)
+	}
+	if breakdown.LongSellValue != exact || breakdown.LongBuyVolume != 1200 {
+		t.Errorf("Unexpected breakdown values: %d, %d", breakdown.LongSellValue, breakdown.LongBuyVolume)
+	}
+	var margin MarginTradingOutstanding
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-10","Code":"72030","ShrtVol":9007199254740993,"IssType":"1"}`), &margin); err != nil {
+		t.Fatalf("Failed to unmarshal margin trading outstanding: %v", err)
+	}
+	if margin.TotalShortBalance != exact {
+		t.Errorf("Precision lost: short balance=%d", margin.TotalShortBalance)
+	}
+	var stock StockPrice
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","Code":"72030","UL":"0","LL":"0","Va":9007199254740993,"AdjFactor":1}`), &stock); err != nil {
+		t.Fatalf("Failed to unmarshal stock price: %v", err)
+	}
+	if stock.TurnoverValue == nil || *stock.TurnoverValue != exact {
+		t.Errorf("Precision lost: turnover value=%v", stock.TurnoverValue)
+	}
+	var futures FuturesPrice
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","Code":"161030018","Va":9007199254740993}`), &futures); err != nil {
+		t.Fatalf("Failed to unmarshal futures price: %v", err)
+	}
+	if futures.TurnoverValue != exact {
+		t.Errorf("Precision lost: futures turnover value=%d", futures.TurnoverValue)
+	}
+}
@@ -1,11 +1,17 @@
 package jquants
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
 	"net/url"
+	"slices"
 	"strconv"
+	"time"
 )
 
 // IndexOptionPrice represents daily price data for Nikkei 225 index options.
@@ -17,29 +23,29 @@ type IndexOptionPrice struct {
 	// Code is the option contract code.
 	Code string
 	// WholeDayOpen is the opening price for the whole trading day.
-	WholeDayOpen *int16
+	WholeDayOpen *int32
 	// WholeDayHigh is the highest price for the whole trading day.
-	WholeDayHigh *int16
+	WholeDayHigh *int32
 	// WholeDayLow is the lowest price for the whole trading day.
-	WholeDayLow *int16
+	WholeDayLow *int32
 	// WholeDayClose is the closing price for the whole trading day.
-	WholeDayClose *int16
+	WholeDayClose *int32
 	// NightSessionOpen is the opening price for the night session.
-	NightSessionOpen *int16
+	NightSessionOpen *int32
 	// NightSessionHigh is the highest price for the night session.
-	NightSessionHigh *int16
+	NightSessionHigh *int32
 	// NightSessionLow is the lowest price for the night session.
-	NightSessionLow *int16
+	NightSessionLow *int32
 	// NightSessionClose is the closing price for the night session.
-	NightSessionClose *int16
+	NightSessionClose *int32
 	// DaySessionOpen is the opening price for the day session.
-	DaySessionOpen *int16
+	DaySessionOpen *int32
 	// DaySessionHigh is the highest price for the day session.
-	DaySessionHigh *int16
+	DaySessionHigh *int32
 	// DaySessionLow is the lowest price for the day session.
-	DaySessionLow *int16
+	DaySessionLow *int32
 	// DaySessionClose is the closing price for the day session.
-	DaySessionClose *int16
+	DaySessionClose *int32
 	// Volume is the total trading volume in contracts.
 	Volume int64
 	// OpenInterest is the number of outstanding contracts.
@@ -49,7 +55,7 @@ type IndexOptionPrice struct {
 	// ContractMonth is the contract expiration month in YYYYMM format.
 	ContractMonth string
 	// StrikePrice is the option strike price.
-	StrikePrice int16
+	StrikePrice int32
 	// VolumeOnlyAuction is the volume from auction-only trades.
 	VolumeOnlyAuction *int64
 	// EmergencyMarginTriggerDivision indicates emergency margin status.
@@ -61,7 +67,7 @@ type IndexOptionPrice struct {
 	// SpecialQuotationDay is the special quotation day (SQ day).
 	SpecialQuotationDay *string
 	// SettlementPrice is the daily settlement price.
-	SettlementPrice *int16
+	SettlementPrice *int32
 	// TheoreticalPrice is the theoretical option price.
 	TheoreticalPrice *json.Number
 	// BaseVolatility is the base volatility used for theoretical price calculation.
@@ -74,12 +80,238 @@ type IndexOptionPrice struct {
 	InterestRate *json.Number
 }
 
+const (
+	putDivision  = 1
+	callDivision = 2
+)
+
+// PutCallRatio computes the put/call ratios of trading volume and open interest for an option snapshot.
+// If contractMonths are given (YYYYMM), only contracts expiring in those months are included, so per-expiry
+// ratios can be computed. A ratio is 0 when the corresponding call total is 0.
+func PutCallRatio(prices []IndexOptionPrice, contractMonths ...string) (volumePCR, oiPCR float64) {
+	var putVolume, callVolume, putOI, callOI int64
+	for _, p := range prices {
+		if len(contractMonths) > 0 && !slices.Contains(contractMonths, p.ContractMonth) {
+			continue
+		}
+		switch p.PutCallDivision {
+		case putDivision:
+			putVolume += p.Volume
+			putOI += p.OpenInterest
+		case callDivision:
+			callVolume += p.Volume
+			callOI += p.OpenInterest
+		}
+	}
+	if callVolume != 0 {
+		volumePCR = float64(putVolume) / float64(callVolume)
+	}
+	if callOI != 0 {
+		oiPCR = float64(putOI) / float64(callOI)
+	}
+	return volumePCR, oiPCR
+}
+
+// Moneyness classifies an option by the position of its strike relative to the underlying price.
+type Moneyness int8
+
+const (
+	// MoneynessUnknown means the underlying price or option type is not available.
+	MoneynessUnknown Moneyness = iota
+	// InTheMoney means the option has intrinsic value beyond the ATM band.
+	InTheMoney
+	// AtTheMoney means the strike lies within the ATM band around the underlying price.
+	AtTheMoney
+	// OutOfTheMoney means the option has no intrinsic value and lies outside the ATM band.
+	OutOfTheMoney
+)
+
+func (m Moneyness) String() string {
+	switch m {
+	case InTheMoney:
+		return "ITM"
+	case AtTheMoney:
+		return "ATM"
+	case OutOfTheMoney:
+		return "OTM"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultATMTolerance is the ATM band used by [IndexOptionPrice.Moneyness], as a fraction of the underlying price.
+const DefaultATMTolerance = 0.005
+
+// Moneyness classifies the option using [DefaultATMTolerance].
+func (p IndexOptionPrice) Moneyness() Moneyness {
+	return p.MoneynessWithTolerance(DefaultATMTolerance)
+}
+
+// MoneynessWithTolerance classifies the option as ATM if the strike is within tolerance × UnderlyingPrice
+// of the underlying price, and as ITM or OTM otherwise. It returns MoneynessUnknown if UnderlyingPrice is nil
+// or unparsable, or PutCallDivision is neither put nor call.
+func (p IndexOptionPrice) MoneynessWithTolerance(tolerance float64) Moneyness {
+	if p.UnderlyingPrice == nil || (p.PutCallDivision != putDivision && p.PutCallDivision != callDivision) {
+		return MoneynessUnknown
+	}
+	underlying, err := p.UnderlyingPrice.Float64()
+	if err != nil {
+		return MoneynessUnknown
+	}
+	diff := underlying - float64(p.StrikePrice)
+	switch {
+	case math.Abs(diff) <= tolerance*underlying:
+		return AtTheMoney
+	case (p.PutCallDivision == callDivision) == (diff > 0):
+		return InTheMoney
+	default:
+		return OutOfTheMoney
+	}
+}
+
+// IVTermStructure maps each contract month (YYYYMM) of an option snapshot to its ATM implied volatility:
+// the ImpliedVolatility of the contracts whose strike is nearest to the underlying price, averaged over
+// puts and calls at that strike. Contracts without an implied volatility or underlying price are ignored,
+// and months without any usable contract are omitted.
+// Iterate the months in order with slices.Sorted(maps.Keys(result)).
+func IVTermStructure(prices []IndexOptionPrice) map[string]float64 {
+	type atm struct {
+		distance float64
+		sum      float64
+		n        int
+	}
+	byMonth := make(map[string]*atm)
+	for _, p := range prices {
+		if p.ImpliedVolatility == nil || p.UnderlyingPrice == nil {
+			continue
+		}
+		iv, err := p.ImpliedVolatility.Float64()
+		if err != nil {
+			continue
+		}
+		underlying, err := p.UnderlyingPrice.Float64()
+		if err != nil {
+			continue
+		}
+		distance := math.Abs(underlying - float64(p.StrikePrice))
+		current, ok := byMonth[p.ContractMonth]
+		switch {
+		case !ok || distance < current.distance:
+			byMonth[p.ContractMonth] = &atm{distance: distance, sum: iv, n: 1}
+		case distance == current.distance:
+			current.sum += iv
+			current.n++
+		}
+	}
+	ivs := make(map[string]float64, len(byMonth))
+	for month, a := range byMonth {
+		ivs[month] = a.sum / float64(a.n)
+	}
+	return ivs
+}
+
+// ParityViolation is a put/call pair whose prices deviate from put-call parity, as reported by [CheckPutCallParity].
+type ParityViolation struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// ContractMonth is the contract month in YYYYMM format.
+	ContractMonth string
+	// StrikePrice is the shared strike of the pair.
+	StrikePrice int32
+	// CallCode and PutCode are the contract codes of the pair.
+	CallCode, PutCode string
+	// Actual is the observed call close minus put close.
+	Actual float64
+	// Expected is the parity value S - K·e^(-rT) from the underlying price, strike, interest rate and time to the SQ day.
+	Expected float64
+	// Deviation is Actual - Expected.
+	Deviation float64
+}
+
+// CheckPutCallParity pairs the calls and puts of an option chain by date, contract month and strike and checks
+// European put-call parity, C - P = S - K·e^(-rT), using WholeDayClose prices, the call's UnderlyingPrice and
+// InterestRate, and the time from Date to SpecialQuotationDay on an ACT/365 basis. InterestRate is read as a
+// percentage, as published. Pairs whose |Deviation| exceeds tolerance (in index points) are returned, sorted by
+// date, contract month and strike. Pairs missing a close, underlying price, rate or SQ day are skipped.
+// The relation ignores dividends, so a tolerance of a few dozen points is typical for deep strikes.
+func CheckPutCallParity(chain []IndexOptionPrice, tolerance float64) []ParityViolation {
+	type key struct {
+		date, month string
+		strike      int32
+	}
+	calls := make(map[key]IndexOptionPrice)
+	puts := make(map[key]IndexOptionPrice)
+	for _, p := range chain {
+		k := key{p.Date, p.ContractMonth, p.StrikePrice}
+		switch p.PutCallDivision {
+		case callDivision:
+			calls[k] = p
+		case putDivision:
+			puts[k] = p
+		}
+	}
+	violations := make([]ParityViolation, 0)
+	for k, call := range calls {
+		put, ok := puts[k]
+		if !ok || call.WholeDayClose == nil || put.WholeDayClose == nil {
+			continue
+		}
+		expected, ok := parityValue(call)
+		if !ok {
+			continue
+		}
+		actual := float64(*call.WholeDayClose) - float64(*put.WholeDayClose)
+		if math.Abs(actual-expected) <= tolerance {
+			continue
+		}
+		violations = append(violations, ParityViolation{
+			Date:          k.date,
+			ContractMonth: k.month,
+			StrikePrice:   k.strike,
+			CallCode:      call.Code,
+			PutCode:       put.Code,
+			Actual:        actual,
+			Expected:      expected,
+			Deviation:     actual - expected,
+		})
+	}
+	slices.SortFunc(violations, func(a, b ParityViolation) int {
+		return cmp.Or(cmp.Compare(a.Date, b.Date), cmp.Compare(a.ContractMonth, b.ContractMonth), cmp.Compare(a.StrikePrice, b.StrikePrice))
+	})
+	return violations
+}
+
+// parityValue returns S - K·e^(-rT) for p, or false if an input is missing or invalid.
+func parityValue(p IndexOptionPrice) (float64, bool) {
+	if p.UnderlyingPrice == nil || p.InterestRate == nil || p.SpecialQuotationDay == nil {
+		return 0, false
+	}
+	underlying, err := p.UnderlyingPrice.Float64()
+	if err != nil {
+		return 0, false
+	}
+	rate, err := p.InterestRate.Float64()
+	if err != nil {
+		return 0, false
+	}
+	date, err := time.Parse(dateLayout, p.Date)
+	if err != nil {
+		return 0, false
+	}
+	sq, err := time.Parse(dateLayout, *p.SpecialQuotationDay)
+	if err != nil || sq.Before(date) {
+		return 0, false
+	}
+	years := sq.Sub(date).Hours() / 24 / 365
+	return underlying - float64(p.StrikePrice)*math.Exp(-rate/100*years), true
+}
+
 // unmarshaler accumulates errors during unmarshaling, allowing cleaner code flow.
 type unmarshaler struct {
 	err error
 }
 
-func (u *unmarshaler) price(v interface{}) *int16 {
+func (u *unmarshaler) price(v interface{}) *int32 {
 	if u.err != nil {
 		return nil
 	}
@@ -97,6 +329,20 @@ func (u *unmarshaler) volume(v interface{}) *int64 {
 	return result
 }
 
+// integer converts a count or yen amount to an int64 without a float64 intermediate, so values above 2^53
+// stay exact. A missing value is zero.
+func (u *unmarshaler) integer(n json.Number) int64 {
+	if u.err != nil || n == "" {
+		return 0
+	}
+	i, err := unmarshalInt64(&n)
+	if err != nil {
+		u.err = err
+		return 0
+	}
+	return *i
+}
+
 func (u *unmarshaler) jsonNumber(v interface{}) *json.Number {
 	if u.err != nil {
 		return nil
@@ -106,6 +352,15 @@ func (u *unmarshaler) jsonNumber(v interface{}) *json.Number {
 	return result
 }
 
+func (u *unmarshaler) number(v interface{}) *json.Number {
+	if u.err != nil {
+		return nil
+	}
+	result, err := unmarshalNumber(v)
+	u.err = err
+	return result
+}
+
 func (iop *IndexOptionPrice) UnmarshalJSON(b []byte) error {
 	var raw struct {
 		Date                           string      `json:"Date"`
@@ -122,9 +377,9 @@ func (iop *IndexOptionPrice) UnmarshalJSON(b []byte) error {
 		DaySessionHigh                 interface{} `json:"AH"`
 		DaySessionLow                  interface{} `json:"AL"`
 		DaySessionClose                interface{} `json:"AC"`
-		Volume                         float64     `json:"Vo"`
-		OpenInterest                   float64     `json:"OI"`
-		TurnoverValue                  float64     `json:"Va"`
+		Volume                         json.Number `json:"Vo"`
+		OpenInterest                   json.Number `json:"OI"`
+		TurnoverValue                  json.Number `json:"Va"`
 		ContractMonth                  string      `json:"CM"`
 		StrikePrice                    float64     `json:"Strike"`
 		VolumeOnlyAuction              interface{} `json:"VoOA"`
@@ -163,11 +418,15 @@ func (iop *IndexOptionPrice) UnmarshalJSON(b []byte) error {
 	iop.DaySessionHigh = u.price(raw.DaySessionHigh)
 	iop.DaySessionLow = u.price(raw.DaySessionLow)
 	iop.DaySessionClose = u.price(raw.DaySessionClose)
-	iop.Volume = int64(raw.Volume)
-	iop.OpenInterest = int64(raw.OpenInterest)
-	iop.TurnoverValue = int64(raw.TurnoverValue)
+	iop.Volume = u.integer(raw.Volume)
+	iop.OpenInterest = u.integer(raw.OpenInterest)
+	iop.TurnoverValue = u.integer(raw.TurnoverValue)
 	iop.ContractMonth = raw.ContractMonth
-	iop.StrikePrice = int16(raw.StrikePrice)
+	strikePrice, err := unmarshalPrice(raw.StrikePrice)
+	if err != nil {
+		return fmt.Errorf("failed to parse strike price: %w", err)
+	}
+	iop.StrikePrice = *strikePrice
 	iop.VolumeOnlyAuction = u.volume(raw.VolumeOnlyAuction)
 	iop.EmergencyMarginTriggerDivision = raw.EmergencyMarginTriggerDivision
 	iop.PutCallDivision = int8(putCallDivision)
@@ -183,11 +442,13 @@ func (iop *IndexOptionPrice) UnmarshalJSON(b []byte) error {
 	return u.err
 }
 
-func unmarshalPrice(value interface{}) (*int16, error) {
+func unmarshalPrice(value interface{}) (*int32, error) {
 	switch v := value.(type) {
 	case float64:
-		f := float32(v)
-		i := int16(f)
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("unmarshalPrice: %v out of int32 range", v)
+		}
+		i := int32(v)
 		return &i, nil
 	case string:
 		return nil, nil
@@ -227,6 +488,24 @@ func unmarshalJSONNumber(value interface{}) (*json.Number, error) {
 	}
 }
 
+// unmarshalNumber converts a value the financial endpoints send either as a bare number or as a numeric
+// string to a json.Number. Like unmarshalJSONNumber, an empty string (a blank item in the disclosure) and
+// null decode to nil.
+func unmarshalNumber(value interface{}) (*json.Number, error) {
+	s, ok := value.(string)
+	if !ok {
+		return unmarshalJSONNumber(value)
+	}
+	if s == "" {
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return nil, fmt.Errorf("unmarshalNumber: invalid number %q", s)
+	}
+	n := json.Number(s)
+	return &n, nil
+}
+
 func unmarshalTime(value string) *string {
 	if value == "" {
 		return nil
@@ -234,10 +513,26 @@ func unmarshalTime(value string) *string {
 	return &value
 }
 
+// unmarshalDate is the time.Time companion of unmarshalTime: it parses a YYYY-MM-DD date as midnight JST
+// and returns nil for an empty string.
+func unmarshalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, jst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", value)
+	}
+	return &t, nil
+}
+
 // IndexOptionPriceRequest specifies filter parameters for the IndexOptionPrice API.
 type IndexOptionPriceRequest struct {
 	// Date is the trading date to query in YYYY-MM-DD format. Required.
 	Date string
+	// OnlyActive drops contracts with zero Volume and zero OpenInterest.
+	// It is applied client-side after decoding, since the endpoint only filters by date.
+	OnlyActive bool
 }
 
 type indexOptionPriceParameters struct {
@@ -246,6 +541,12 @@ type indexOptionPriceParameters struct {
 }
 
 func (p indexOptionPriceParameters) values() (url.Values, error) {
+	if p.Date == "" {
+		return nil, errors.New("date is required")
+	}
+	if err := checkDate("date", &p.Date); err != nil {
+		return nil, err
+	}
 	v := url.Values{}
 	v.Add("date", p.Date)
 	if p.PaginationKey != nil {
@@ -255,28 +556,49 @@ func (p indexOptionPriceParameters) values() (url.Values, error) {
 }
 
 type indexOptionPriceResponse struct {
-	Data          []IndexOptionPrice `json:"data"`
-	PaginationKey *string            `json:"pagination_key"`
+	Data          records[IndexOptionPrice] `json:"data"`
+	PaginationKey *string                   `json:"pagination_key"`
 }
 
-func (r indexOptionPriceResponse) Items() []IndexOptionPrice { return r.Data }
-func (r indexOptionPriceResponse) NextPageKey() *string      { return r.PaginationKey }
+func (r indexOptionPriceResponse) Items() []IndexOptionPrice       { return r.Data.items }
+func (r indexOptionPriceResponse) NextPageKey() *string            { return r.PaginationKey }
+func (r indexOptionPriceResponse) malformedRecords() []RecordError { return r.Data.errs }
 
 func (c *Client) sendIndexOptionPriceRequest(ctx context.Context, params indexOptionPriceParameters) (indexOptionPriceResponse, error) {
 	var r indexOptionPriceResponse
+	r.Data.skipMalformed = c.skipMalformedRecords
 	resp, err := c.sendRequest(ctx, "/derivatives/bars/daily/options/225", params)
 	if err != nil {
 		return r, fmt.Errorf("failed to send GET request: %w", err)
 	}
 	if resp.StatusCode != 200 {
-		return r, handleErrorResponse(resp)
+		return r, c.handleErrorResponse(resp)
 	}
-	if err = decodeResponse(resp, &r); err != nil {
+	if err = c.decodeResponse(resp, &r); err != nil {
 		return r, fmt.Errorf("failed to decode HTTP response: %w", err)
 	}
+	if params.OnlyActive {
+		r.Data.items = activeOptions(r.Data.items)
+	}
 	return r, nil
 }
 
+// isActive reports whether the contract traded or has open interest.
+func isActive(p IndexOptionPrice) bool {
+	return p.Volume != 0 || p.OpenInterest != 0
+}
+
+// activeOptions returns the contracts that traded or have open interest.
+func activeOptions(prices []IndexOptionPrice) []IndexOptionPrice {
+	active := make([]IndexOptionPrice, 0, len(prices))
+	for _, p := range prices {
+		if isActive(p) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
 // IndexOptionPrice retrieves Nikkei 225 index option prices from the /derivatives/bars/daily/options/225 endpoint.
 // It automatically handles pagination to fetch all matching records.
 func (c *Client) IndexOptionPrice(ctx context.Context, req IndexOptionPriceRequest) ([]IndexOptionPrice, error) {
@@ -288,9 +610,31 @@ func (c *Client) IndexOptionPrice(ctx context.Context, req IndexOptionPriceReque
 
 // IndexOptionPriceWithChannel retrieves Nikkei 225 index option prices and streams each record to the provided channel.
 // The channel is closed when all records have been sent or an error occurs.
+// Records are decoded and sent one at a time, so a market-wide snapshot is never held in memory at once;
+// prefer this over IndexOptionPrice for date queries covering the whole option chain.
 func (c *Client) IndexOptionPriceWithChannel(ctx context.Context, req IndexOptionPriceRequest, ch chan<- IndexOptionPrice) error {
-	return fetchAllPagesWithChannel(ctx, c, ch, func(ctx context.Context, paginationKey *string) (indexOptionPriceResponse, error) {
+	return fetchAllPagesWithChannel(ctx, c, ch, c.streamIndexOptionPricePage(req))
+}
+
+// IndexOptionPriceSeq returns an iterator over index option prices, decoded one at a time like
+// [Client.StockPriceSeq]. OnlyActive is applied as records are decoded.
+func (c *Client) IndexOptionPriceSeq(ctx context.Context, req IndexOptionPriceRequest) iter.Seq2[IndexOptionPrice, error] {
+	return fetchAllPagesSeq(ctx, c, c.streamIndexOptionPricePage(req))
+}
+
+// streamIndexOptionPricePage returns the page fetcher shared by the streaming variants of IndexOptionPrice.
+func (c *Client) streamIndexOptionPricePage(req IndexOptionPriceRequest) func(ctx context.Context, paginationKey *string, emit func(IndexOptionPrice) error) (streamedPage[IndexOptionPrice], error) {
+	return func(ctx context.Context, paginationKey *string, emit func(IndexOptionPrice) error) (streamedPage[IndexOptionPrice], error) {
 		params := indexOptionPriceParameters{IndexOptionPriceRequest: req, PaginationKey: paginationKey}
-		return c.sendIndexOptionPriceRequest(ctx, params)
-	})
+		if req.OnlyActive {
+			send := emit
+			emit = func(p IndexOptionPrice) error {
+				if !isActive(p) {
+					return nil
+				}
+				return send(p)
+			}
+		}
+		return streamPage(ctx, c, "/derivatives/bars/daily/options/225", params, emit)
+	}
 }
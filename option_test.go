@@ -2,6 +2,9 @@ package jquants
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -18,6 +21,20 @@ func TestClient_IndexOptionPrice(t *testing.T) {
 	}
 }
 
+func TestClient_IndexOptionPriceRange(t *testing.T) {
+	client := setupClient(t)
+	resp, err := client.IndexOptionPriceRange(t.Context(), "2025-01-04", "2025-01-07")
+	if err != nil {
+		t.Errorf("Failed to get index option price range: %v", err)
+	}
+	if _, ok := resp["2025-01-05"]; ok {
+		t.Error("Unexpected snapshot for a non-trading day")
+	}
+	if len(resp["2025-01-06"]) == 0 {
+		t.Error("Empty snapshot for a trading day")
+	}
+}
+
 func TestClient_IndexOptionPriceWithChannel(t *testing.T) {
 	date := "2025-01-06"
 	client := setupClient(t)
@@ -38,3 +55,143 @@ func TestClient_IndexOptionPriceWithChannel(t *testing.T) {
 		t.Error("Empty response")
 	}
 }
+
+func TestIndexOptionPrice_UnmarshalJSON(t *testing.T) {
+	var p IndexOptionPrice
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","Code":"130010018","Strike":40000,"PCDiv":"2","C":33000,"Settle":40500}`), &p); err != nil {
+		t.Fatalf("Failed to unmarshal index option price: %v", err)
+	}
+	if p.StrikePrice != 40000 {
+		t.Errorf("Unexpected strike price: got %d, want 40000", p.StrikePrice)
+	}
+	if p.WholeDayClose == nil || *p.WholeDayClose != 33000 || p.SettlementPrice == nil || *p.SettlementPrice != 40500 {
+		t.Errorf("Unexpected prices above the int16 range: close=%v, settlement=%v", p.WholeDayClose, p.SettlementPrice)
+	}
+	sessions := `{"Date":"2025-01-06","PCDiv":"1","Strike":42000,"O":40001,"H":40002,"L":40003,"C":40004,"EO":40005,"EH":40006,"EL":40007,"EC":40008,"AO":40009,"AH":40010,"AL":40011,"AC":40012}`
+	if err := json.Unmarshal([]byte(sessions), &p); err != nil {
+		t.Fatalf("Failed to unmarshal index option price: %v", err)
+	}
+	fields := []*int32{p.WholeDayOpen, p.WholeDayHigh, p.WholeDayLow, p.WholeDayClose, p.NightSessionOpen, p.NightSessionHigh,
+		p.NightSessionLow, p.NightSessionClose, p.DaySessionOpen, p.DaySessionHigh, p.DaySessionLow, p.DaySessionClose}
+	for i, f := range fields {
+		if want := int32(40001 + i); f == nil || *f != want {
+			t.Errorf("Unexpected session price %d: got %v, want %d", i, f, want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"Date":"2025-01-06","PCDiv":"1","Strike":3000000000}`), &p); err == nil {
+		t.Errorf("Expected error for a strike price out of the int32 range, got %d", p.StrikePrice)
+	}
+}
+
+func TestActiveOptions(t *testing.T) {
+	prices := []IndexOptionPrice{
+		{Code: "dead"},
+		{Code: "traded", Volume: 10},
+		{Code: "open", OpenInterest: 5},
+	}
+	active := activeOptions(prices)
+	if len(active) != 2 || active[0].Code != "traded" || active[1].Code != "open" {
+		t.Errorf("Unexpected active options: %+v", active)
+	}
+}
+
+func TestPutCallRatio(t *testing.T) {
+	prices := []IndexOptionPrice{
+		{ContractMonth: "202501", PutCallDivision: 1, Volume: 30, OpenInterest: 10},
+		{ContractMonth: "202501", PutCallDivision: 2, Volume: 20, OpenInterest: 40},
+		{ContractMonth: "202502", PutCallDivision: 1, Volume: 50, OpenInterest: 50},
+	}
+	volumePCR, oiPCR := PutCallRatio(prices)
+	if volumePCR != 4 || oiPCR != 1.5 {
+		t.Errorf("Unexpected ratios: volume=%v, oi=%v", volumePCR, oiPCR)
+	}
+	volumePCR, oiPCR = PutCallRatio(prices, "202501")
+	if volumePCR != 1.5 || oiPCR != 0.25 {
+		t.Errorf("Unexpected per-expiry ratios: volume=%v, oi=%v", volumePCR, oiPCR)
+	}
+	volumePCR, oiPCR = PutCallRatio(prices, "202502")
+	if volumePCR != 0 || oiPCR != 0 {
+		t.Errorf("Expected zero ratios without calls: volume=%v, oi=%v", volumePCR, oiPCR)
+	}
+}
+
+func TestIndexOptionPrice_Moneyness(t *testing.T) {
+	underlying := json.Number("30000")
+	tests := []struct {
+		name  string
+		price IndexOptionPrice
+		want  Moneyness
+	}{
+		{"itm call", IndexOptionPrice{StrikePrice: 28000, PutCallDivision: callDivision, UnderlyingPrice: &underlying}, InTheMoney},
+		{"otm call", IndexOptionPrice{StrikePrice: 32000, PutCallDivision: callDivision, UnderlyingPrice: &underlying}, OutOfTheMoney},
+		{"itm put", IndexOptionPrice{StrikePrice: 32000, PutCallDivision: putDivision, UnderlyingPrice: &underlying}, InTheMoney},
+		{"otm put", IndexOptionPrice{StrikePrice: 28000, PutCallDivision: putDivision, UnderlyingPrice: &underlying}, OutOfTheMoney},
+		{"atm", IndexOptionPrice{StrikePrice: 30125, PutCallDivision: putDivision, UnderlyingPrice: &underlying}, AtTheMoney},
+		{"no underlying", IndexOptionPrice{StrikePrice: 28000, PutCallDivision: callDivision}, MoneynessUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.Moneyness(); got != tt.want {
+				t.Errorf("Unexpected moneyness: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIVTermStructure(t *testing.T) {
+	underlying := json.Number("30000")
+	iv := func(s string) *json.Number { n := json.Number(s); return &n }
+	prices := []IndexOptionPrice{
+		{ContractMonth: "202402", StrikePrice: 30000, PutCallDivision: putDivision, UnderlyingPrice: &underlying, ImpliedVolatility: iv("20")},
+		{ContractMonth: "202402", StrikePrice: 30000, PutCallDivision: callDivision, UnderlyingPrice: &underlying, ImpliedVolatility: iv("22")},
+		{ContractMonth: "202402", StrikePrice: 31000, PutCallDivision: callDivision, UnderlyingPrice: &underlying, ImpliedVolatility: iv("18")},
+		{ContractMonth: "202403", StrikePrice: 29500, PutCallDivision: putDivision, UnderlyingPrice: &underlying, ImpliedVolatility: iv("19")},
+		{ContractMonth: "202404", StrikePrice: 30000, PutCallDivision: putDivision, UnderlyingPrice: &underlying},
+		{ContractMonth: "202405", StrikePrice: 30000, PutCallDivision: putDivision, ImpliedVolatility: iv("25")},
+	}
+	got := IVTermStructure(prices)
+	if len(got) != 2 || got["202402"] != 21 || got["202403"] != 19 {
+		t.Errorf("Unexpected term structure: %v", got)
+	}
+}
+
+func TestCheckPutCallParity(t *testing.T) {
+	underlying, rate, sq := json.Number("30000"), json.Number("0"), "2024-02-09"
+	closePrice := func(v int32) *int32 { return &v }
+	option := func(code string, division int8, strike int32, close *int32) IndexOptionPrice {
+		return IndexOptionPrice{
+			Date: "2024-01-10", Code: code, ContractMonth: "202402", StrikePrice: strike, PutCallDivision: division,
+			WholeDayClose: close, UnderlyingPrice: &underlying, InterestRate: &rate, SpecialQuotationDay: &sq,
+		}
+	}
+	chain := []IndexOptionPrice{
+		option("C29000", callDivision, 29000, closePrice(1100)),
+		option("P29000", putDivision, 29000, closePrice(90)),
+		option("C31000", callDivision, 31000, closePrice(50)),
+		option("P31000", putDivision, 31000, closePrice(1200)),
+		option("C32000", callDivision, 32000, closePrice(10)),
+		option("P32000", putDivision, 32000, nil),
+	}
+	got := CheckPutCallParity(chain, 20)
+	if len(got) != 1 {
+		t.Fatalf("Unexpected violations: %+v", got)
+	}
+	if got[0].StrikePrice != 31000 || got[0].Expected != -1000 || got[0].Deviation != -150 || got[0].PutCode != "P31000" {
+		t.Errorf("Unexpected violation: %+v", got[0])
+	}
+}
+
+func TestIndexOptionPriceParameters_Date(t *testing.T) {
+	transport := &flakyTransport{}
+	client := NewClient(BaseURL, "", WithHTTPClient(&http.Client{Transport: transport}))
+	_, err := client.IndexOptionPrice(t.Context(), IndexOptionPriceRequest{})
+	if err == nil || !strings.Contains(err.Error(), "date is required") {
+		t.Errorf("Expected a missing date error, got %v", err)
+	}
+	if _, err := (indexOptionPriceParameters{IndexOptionPriceRequest: IndexOptionPriceRequest{Date: "20250106"}}).values(); err == nil {
+		t.Error("Expected error for a date not in YYYY-MM-DD format")
+	}
+	if transport.calls != 0 {
+		t.Errorf("Expected no HTTP request for an invalid date, got %d", transport.calls)
+	}
+}
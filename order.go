@@ -0,0 +1,63 @@
+package jquants
+
+import "slices"
+
+// ordered is implemented by record types that [WithStableOrder] can sort.
+type ordered interface {
+	orderKey() recordKey
+}
+
+// recordKey is a record's sort key, compared field by field: its date, the identifier that orders records of
+// the same date (code, sector or section), and the fields that break the remaining ties for types that can
+// have several records per date and identifier. Unused fields are empty.
+type recordKey [5]string
+
+func (i IssueInformation) orderKey() recordKey         { return recordKey{i.Date, i.Code} }
+func (p StockPrice) orderKey() recordKey               { return recordKey{p.Date, p.Code} }
+func (p MorningSessionStockPrice) orderKey() recordKey { return recordKey{p.Date, p.Code} }
+func (i InvestorType) orderKey() recordKey             { return recordKey{i.StartDate, i.Section, i.PublishedDate} }
+func (m MarginTradingOutstanding) orderKey() recordKey { return recordKey{m.Date, m.Code} }
+func (v ShortSellingValue) orderKey() recordKey        { return recordKey{v.Date, v.Sector33Code} }
+func (p IndexPrice) orderKey() recordKey               { return recordKey{p.Date, p.Code} }
+func (p TopixPrice) orderKey() recordKey               { return recordKey{p.Date} }
+func (b Breakdown) orderKey() recordKey                { return recordKey{b.Date, b.Code} }
+func (a EarningsAnnouncement) orderKey() recordKey     { return recordKey{a.Date, a.Code} }
+
+// Options and futures have a second record for a contract on days an emergency margin is triggered.
+func (p IndexOptionPrice) orderKey() recordKey {
+	return recordKey{p.Date, p.Code, p.EmergencyMarginTriggerDivision}
+}
+
+func (p FuturesPrice) orderKey() recordKey {
+	return recordKey{p.Date, p.Code, p.EmergencyMarginTriggerDivision}
+}
+
+// A code has one short position per holder and calculation date, and a holder may report it more than once.
+func (p ShortSellingPosition) orderKey() recordKey {
+	return recordKey{p.CalculatedDate, p.Code, p.ShortSellerName, p.DiscretionaryInvestmentContractorName, p.DisclosedDate}
+}
+
+// A code can announce several dividends on one day, and a revision reuses the reference number.
+func (d Dividend) orderKey() recordKey {
+	return recordKey{d.AnnouncementDate, d.Code, d.AnnouncementTime, d.ReferenceNumber, d.StatusCode}
+}
+
+// A code can disclose several statements (a summary and its corrections) on one day.
+func (s FinancialStatement) orderKey() recordKey {
+	return recordKey{s.DisclosedDate, s.Code, s.DisclosedTime, s.DisclosureNumber}
+}
+
+// sortStable sorts data by orderKey. Only records whose keys are equal in every field keep their arrival
+// order. It does nothing if T does not implement ordered.
+func sortStable[T any](data []T) {
+	if len(data) == 0 {
+		return
+	}
+	if _, ok := any(data[0]).(ordered); !ok {
+		return
+	}
+	slices.SortStableFunc(data, func(a, b T) int {
+		aKey, bKey := any(a).(ordered).orderKey(), any(b).(ordered).orderKey()
+		return slices.Compare(aKey[:], bKey[:])
+	})
+}
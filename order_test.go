@@ -0,0 +1,64 @@
+package jquants
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+type stockPricePage struct {
+	items []StockPrice
+	next  *string
+}
+
+func (p stockPricePage) Items() []StockPrice  { return p.items }
+func (p stockPricePage) NextPageKey() *string { return p.next }
+
+func TestFetchAllPages_StableOrder(t *testing.T) {
+	client := NewClient(BaseURL, "", WithStableOrder(true))
+	key := "next"
+	data, err := fetchAllPages(t.Context(), client, func(ctx context.Context, paginationKey *string) (stockPricePage, error) {
+		if paginationKey == nil {
+			return stockPricePage{items: []StockPrice{{Date: "2024-01-05", Code: "13010"}, {Date: "2024-01-04", Code: "72030"}}, next: &key}, nil
+		}
+		return stockPricePage{items: []StockPrice{{Date: "2024-01-04", Code: "13010"}}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := make([]string, 0, len(data))
+	for _, p := range data {
+		got = append(got, p.Date+"/"+p.Code)
+	}
+	want := []string{"2024-01-04/13010", "2024-01-04/72030", "2024-01-05/13010"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Unexpected order: got %v, want %v", got, want)
+	}
+}
+
+func TestSortStable_TieBreakers(t *testing.T) {
+	positions := []ShortSellingPosition{
+		{CalculatedDate: "2024-01-04", Code: "72030", ShortSellerName: "B"},
+		{CalculatedDate: "2024-01-04", Code: "72030", ShortSellerName: "A"},
+	}
+	sortStable(positions)
+	if positions[0].ShortSellerName != "A" {
+		t.Errorf("Expected short positions ordered by holder: %+v", positions)
+	}
+	statements := []FinancialStatement{
+		{DisclosedDate: "2024-05-08", Code: "72030", DisclosureNumber: "20240508000002"},
+		{DisclosedDate: "2024-05-08", Code: "72030", DisclosureNumber: "20240508000001"},
+	}
+	sortStable(statements)
+	if statements[0].DisclosureNumber != "20240508000001" {
+		t.Errorf("Expected statements ordered by disclosure number: %+v", statements)
+	}
+	dividends := []Dividend{
+		{AnnouncementDate: "2024-05-08", Code: "72030", ReferenceNumber: "2"},
+		{AnnouncementDate: "2024-05-08", Code: "72030", ReferenceNumber: "1"},
+	}
+	sortStable(dividends)
+	if dividends[0].ReferenceNumber != "1" {
+		t.Errorf("Expected dividends ordered by reference number: %+v", dividends)
+	}
+}
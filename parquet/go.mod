@@ -0,0 +1,19 @@
+module github.com/s-shiga/jquants-go/v2/parquet
+
+go 1.24.2
+
+require (
+	github.com/parquet-go/parquet-go v0.25.1
+	github.com/s-shiga/jquants-go/v2 v2.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/time v0.14.0 // indirect
+)
+
+replace github.com/s-shiga/jquants-go/v2 => ../
@@ -0,0 +1,127 @@
+// Package parquet writes J-Quants data as Apache Parquet files for analytical workloads
+// (DuckDB, Spark, Polars, etc.).
+//
+// It is a separate module so that the core jquants module stays free of the
+// Parquet dependency for users who do not need it.
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	pq "github.com/parquet-go/parquet-go"
+
+	"github.com/s-shiga/jquants-go/v2"
+)
+
+// stockPriceRow is the Parquet schema for jquants.StockPrice.
+// Nil price and volume pointers are written as Parquet nulls; prices are stored as DOUBLE.
+type stockPriceRow struct {
+	Date             string   `parquet:"date"`
+	Code             string   `parquet:"code"`
+	Open             *float64 `parquet:"open,optional"`
+	High             *float64 `parquet:"high,optional"`
+	Low              *float64 `parquet:"low,optional"`
+	Close            *float64 `parquet:"close,optional"`
+	UpperLimit       bool     `parquet:"upper_limit"`
+	LowerLimit       bool     `parquet:"lower_limit"`
+	Volume           *int64   `parquet:"volume,optional"`
+	TurnoverValue    *int64   `parquet:"turnover_value,optional"`
+	AdjustmentFactor float64  `parquet:"adjustment_factor"`
+	AdjustedOpen     *float64 `parquet:"adjusted_open,optional"`
+	AdjustedHigh     *float64 `parquet:"adjusted_high,optional"`
+	AdjustedLow      *float64 `parquet:"adjusted_low,optional"`
+	AdjustedClose    *float64 `parquet:"adjusted_close,optional"`
+	AdjustedVolume   *int64   `parquet:"adjusted_volume,optional"`
+}
+
+// rowsPerRowGroup bounds the rows a StockPriceWriter buffers before flushing a row group to the output.
+const rowsPerRowGroup = 128 * 1024
+
+// WriteStockPrices writes prices to w as a single Parquet file.
+func WriteStockPrices(w io.Writer, prices []jquants.StockPrice) error {
+	writer := NewStockPriceWriter(w)
+	if err := writer.Write(prices...); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// StockPriceWriter writes stock prices to a Parquet file incrementally, so that a multi-year pull streamed
+// with jquants.Client.StockPriceSeq or StockPriceWithChannel does not have to be held in memory.
+// Rows are flushed in row groups of about 128K rows. The file is complete only after Close.
+type StockPriceWriter struct {
+	writer *pq.GenericWriter[stockPriceRow]
+}
+
+// NewStockPriceWriter creates a StockPriceWriter that writes a Parquet file to w.
+func NewStockPriceWriter(w io.Writer) *StockPriceWriter {
+	return &StockPriceWriter{writer: pq.NewGenericWriter[stockPriceRow](w, pq.MaxRowsPerRowGroup(rowsPerRowGroup))}
+}
+
+// Write appends prices to the file.
+func (sw *StockPriceWriter) Write(prices ...jquants.StockPrice) error {
+	rows := make([]stockPriceRow, 0, len(prices))
+	for _, p := range prices {
+		row, err := newStockPriceRow(p)
+		if err != nil {
+			return fmt.Errorf("failed to convert stock price %s %s: %w", p.Code, p.Date, err)
+		}
+		rows = append(rows, row)
+	}
+	if _, err := sw.writer.Write(rows); err != nil {
+		return fmt.Errorf("failed to write parquet rows: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the buffered rows and writes the Parquet footer. It does not close the underlying writer.
+func (sw *StockPriceWriter) Close() error {
+	if err := sw.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close parquet writer: %w", err)
+	}
+	return nil
+}
+
+func newStockPriceRow(p jquants.StockPrice) (stockPriceRow, error) {
+	c := converter{}
+	row := stockPriceRow{
+		Date:           p.Date,
+		Code:           p.Code,
+		Open:           c.double(p.Open),
+		High:           c.double(p.High),
+		Low:            c.double(p.Low),
+		Close:          c.double(p.Close),
+		UpperLimit:     p.UpperLimit,
+		LowerLimit:     p.LowerLimit,
+		Volume:         p.Volume,
+		TurnoverValue:  p.TurnoverValue,
+		AdjustedOpen:   c.double(p.AdjustedOpen),
+		AdjustedHigh:   c.double(p.AdjustedHigh),
+		AdjustedLow:    c.double(p.AdjustedLow),
+		AdjustedClose:  c.double(p.AdjustedClose),
+		AdjustedVolume: p.AdjustedVolume,
+	}
+	if factor := c.double(&p.AdjustmentFactor); factor != nil {
+		row.AdjustmentFactor = *factor
+	}
+	return row, c.err
+}
+
+// converter accumulates errors while converting json.Number values, like the unmarshaler in the core package.
+type converter struct {
+	err error
+}
+
+func (c *converter) double(n *json.Number) *float64 {
+	if c.err != nil || n == nil || *n == "" {
+		return nil
+	}
+	f, err := n.Float64()
+	c.err = err
+	if err != nil {
+		return nil
+	}
+	return &f
+}
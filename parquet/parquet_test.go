@@ -0,0 +1,58 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	pq "github.com/parquet-go/parquet-go"
+
+	"github.com/s-shiga/jquants-go/v2"
+)
+
+func TestWriteStockPrices(t *testing.T) {
+	open, factor := json.Number("1000"), json.Number("1")
+	volume := int64(100)
+	prices := []jquants.StockPrice{
+		{Date: "2025-01-06", Code: "13010", Open: &open, Volume: &volume, AdjustmentFactor: factor, UpperLimit: true},
+		{Date: "2025-01-07", Code: "13010", AdjustmentFactor: factor},
+	}
+	var buf bytes.Buffer
+	if err := WriteStockPrices(&buf, prices); err != nil {
+		t.Fatalf("Failed to write parquet: %v", err)
+	}
+	rows, err := pq.Read[stockPriceRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read parquet: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Unexpected number of rows: %d", len(rows))
+	}
+	if rows[0].Open == nil || *rows[0].Open != 1000 || !rows[0].UpperLimit || rows[0].Volume == nil || *rows[0].Volume != 100 {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Open != nil || rows[1].Volume != nil {
+		t.Errorf("Expected nulls for non-trading day: %+v", rows[1])
+	}
+}
+
+func TestStockPriceWriter(t *testing.T) {
+	factor := json.Number("1")
+	var buf bytes.Buffer
+	writer := NewStockPriceWriter(&buf)
+	for _, date := range []string{"2025-01-06", "2025-01-07", "2025-01-08"} {
+		if err := writer.Write(jquants.StockPrice{Date: date, Code: "13010", AdjustmentFactor: factor}); err != nil {
+			t.Fatalf("Failed to write stock price: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+	rows, err := pq.Read[stockPriceRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read parquet: %v", err)
+	}
+	if len(rows) != 3 || rows[2].Date != "2025-01-08" || rows[2].AdjustmentFactor != 1 {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
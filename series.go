@@ -0,0 +1,413 @@
+package jquants
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OHLCV is a flattened daily bar without pointer fields.
+// It is intended for charting libraries and other consumers that cannot handle nil values.
+type OHLCV struct {
+	// Date is the trading date in YYYY-MM-DD format.
+	Date string
+	// Code is the security code (ticker symbol).
+	Code string
+	// Open is the opening price (zero if HasData is false).
+	Open float64
+	// High is the highest price of the day (zero if HasData is false).
+	High float64
+	// Low is the lowest price of the day (zero if HasData is false).
+	Low float64
+	// Close is the closing price (zero if HasData is false).
+	Close float64
+	// Volume is the trading volume in shares (zero if HasData is false).
+	Volume int64
+	// HasData reports whether trading occurred and the price fields are populated.
+	HasData bool
+}
+
+// SplitAdjusted converts a StockPrice series into two parallel OHLCV series:
+// one built from the unadjusted prices and one from the split-adjusted prices.
+// Both returned slices have the same length and order as prices.
+func SplitAdjusted(prices []StockPrice) (raw, adjusted []OHLCV) {
+	raw = make([]OHLCV, 0, len(prices))
+	adjusted = make([]OHLCV, 0, len(prices))
+	for _, p := range prices {
+		raw = append(raw, newOHLCV(p.Date, p.Code, p.Open, p.High, p.Low, p.Close, p.Volume))
+		adjusted = append(adjusted, newOHLCV(p.Date, p.Code, p.AdjustedOpen, p.AdjustedHigh, p.AdjustedLow, p.AdjustedClose, p.AdjustedVolume))
+	}
+	return raw, adjusted
+}
+
+func newOHLCV(date, code string, open, high, low, close *json.Number, volume *int64) OHLCV {
+	bar := OHLCV{Date: date, Code: code}
+	if open == nil || high == nil || low == nil || close == nil {
+		return bar
+	}
+	var err error
+	if bar.Open, err = open.Float64(); err != nil {
+		return OHLCV{Date: date, Code: code}
+	}
+	if bar.High, err = high.Float64(); err != nil {
+		return OHLCV{Date: date, Code: code}
+	}
+	if bar.Low, err = low.Float64(); err != nil {
+		return OHLCV{Date: date, Code: code}
+	}
+	if bar.Close, err = close.Float64(); err != nil {
+		return OHLCV{Date: date, Code: code}
+	}
+	if volume != nil {
+		bar.Volume = *volume
+	}
+	bar.HasData = true
+	return bar
+}
+
+// delistedGap is how far the last record of a series may lie before the latest trading day, in calendar days,
+// before SeriesInfo infers that the code was delisted. It leaves room for trading halts and long holidays.
+const delistedGap = 14 * 24 * time.Hour
+
+// SeriesInfo returns the first and last dates (YYYY-MM-DD) of a single code's series, which may be unordered.
+// delisted reports whether the series ends more than two weeks before latestTradingDay (e.g., the last
+// trading day of the queried range), which means the code most likely stopped trading within the range.
+// An empty series returns empty dates and false.
+func SeriesInfo(prices []StockPrice, latestTradingDay string) (first, last string, delisted bool) {
+	if len(prices) == 0 {
+		return "", "", false
+	}
+	first, last = prices[0].Date, prices[0].Date
+	for _, p := range prices[1:] {
+		first, last = min(first, p.Date), max(last, p.Date)
+	}
+	lastDate, err := time.Parse(dateLayout, last)
+	if err != nil {
+		return first, last, false
+	}
+	latest, err := time.Parse(dateLayout, latestTradingDay)
+	if err != nil {
+		return first, last, false
+	}
+	return first, last, latest.Sub(lastDate) > delistedGap
+}
+
+// LimitDirection indicates whether a price limit was hit on the upside or the downside.
+type LimitDirection int8
+
+const (
+	// LimitUp means the stock hit the daily upper price limit.
+	LimitUp LimitDirection = 1
+	// LimitDown means the stock hit the daily lower price limit.
+	LimitDown LimitDirection = -1
+)
+
+func (d LimitDirection) String() string {
+	switch d {
+	case LimitUp:
+		return "limit-up"
+	case LimitDown:
+		return "limit-down"
+	default:
+		return "unknown"
+	}
+}
+
+// LimitStreak is a run of consecutive trading days on which a security hit the same daily price limit.
+type LimitStreak struct {
+	// Code is the security code (ticker symbol).
+	Code string
+	// Direction is the side of the price limit that was hit.
+	Direction LimitDirection
+	// StartDate is the first day of the streak in YYYY-MM-DD format.
+	StartDate string
+	// EndDate is the last day of the streak in YYYY-MM-DD format.
+	EndDate string
+	// Days is the number of limit days in the streak.
+	Days int
+}
+
+// LimitStreaks finds runs of consecutive limit-up or limit-down days.
+// The input is sorted by code and date before scanning, so it may be unordered and contain several codes.
+// Only a record without a limit (or with the opposite limit) breaks a streak; dates absent from the
+// series do not. Single-day runs are included with Days set to 1.
+func LimitStreaks(prices []StockPrice) []LimitStreak {
+	sorted := slices.Clone(prices)
+	slices.SortStableFunc(sorted, func(a, b StockPrice) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), cmp.Compare(a.Date, b.Date))
+	})
+	streaks := make([]LimitStreak, 0)
+	var current *LimitStreak
+	for _, p := range sorted {
+		var direction LimitDirection
+		switch {
+		case p.UpperLimit:
+			direction = LimitUp
+		case p.LowerLimit:
+			direction = LimitDown
+		}
+		if current != nil && (direction == 0 || direction != current.Direction || p.Code != current.Code) {
+			streaks = append(streaks, *current)
+			current = nil
+		}
+		if direction == 0 {
+			continue
+		}
+		if current == nil {
+			current = &LimitStreak{Code: p.Code, Direction: direction, StartDate: p.Date}
+		}
+		current.EndDate = p.Date
+		current.Days++
+	}
+	if current != nil {
+		streaks = append(streaks, *current)
+	}
+	return streaks
+}
+
+// RollingMean returns the simple moving average of the adjusted close over the last window trading days.
+// The result has the same length as prices and result[i] corresponds to prices[i]; prices should hold a
+// single code's series and are processed in chronological order.
+// Windows count trading days, not calendar days: records without an adjusted close (no-trade days) are skipped
+// and left empty in the result, as are positions before a full window is available.
+// Sums are accumulated exactly and rounded to float64 precision only once per output.
+func RollingMean(prices []StockPrice, window int) []json.Number {
+	return rolling(prices, window, func(values []*big.Rat) json.Number {
+		return formatRat(mean(values))
+	})
+}
+
+// RollingStdDev returns the sample standard deviation (n-1 denominator) of the adjusted close over the
+// last window trading days. Windowing and alignment are the same as [RollingMean]; window must be at least 2.
+func RollingStdDev(prices []StockPrice, window int) []json.Number {
+	if window < 2 {
+		return make([]json.Number, len(prices))
+	}
+	return rolling(prices, window, func(values []*big.Rat) json.Number {
+		m := mean(values)
+		variance := new(big.Rat)
+		for _, v := range values {
+			d := new(big.Rat).Sub(v, m)
+			variance.Add(variance, d.Mul(d, d))
+		}
+		variance.Quo(variance, big.NewRat(int64(len(values)-1), 1))
+		f, _ := variance.Float64()
+		return json.Number(strconv.FormatFloat(math.Sqrt(f), 'f', -1, 64))
+	})
+}
+
+// RelativeVolume returns each day's adjusted volume divided by the average adjusted volume of the
+// preceding window trading days ("RVOL"). The result is aligned with prices like [RollingMean].
+// Days without a volume and zero-volume days are excluded from the baseline, so an illiquid stretch does
+// not make the next trade look like a spike. Positions without a volume, zero-volume days and positions
+// without window baseline days before them are NaN.
+func RelativeVolume(prices []StockPrice, window int) []float64 {
+	result := make([]float64, len(prices))
+	for i := range result {
+		result[i] = math.NaN()
+	}
+	if window < 1 {
+		return result
+	}
+	baseline := make([]int64, 0, window)
+	var sum int64
+	for _, i := range chronological(prices) {
+		v := prices[i].AdjustedVolume
+		if v == nil || *v == 0 {
+			continue
+		}
+		if len(baseline) == window {
+			result[i] = float64(*v) / (float64(sum) / float64(window))
+			sum -= baseline[0]
+			baseline = baseline[1:]
+		}
+		baseline = append(baseline, *v)
+		sum += *v
+	}
+	return result
+}
+
+// chronological returns the indices of prices ordered by date.
+func chronological(prices []StockPrice) []int {
+	order := make([]int, len(prices))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(prices[a].Date, prices[b].Date) })
+	return order
+}
+
+func rolling(prices []StockPrice, window int, stat func(values []*big.Rat) json.Number) []json.Number {
+	result := make([]json.Number, len(prices))
+	if window < 1 {
+		return result
+	}
+	values := make([]*big.Rat, 0, window)
+	for _, i := range chronological(prices) {
+		c := prices[i].AdjustedClose
+		if c == nil {
+			continue
+		}
+		v, ok := new(big.Rat).SetString(c.String())
+		if !ok {
+			continue
+		}
+		if len(values) == window {
+			values = values[1:]
+		}
+		values = append(values, v)
+		if len(values) == window {
+			result[i] = stat(values)
+		}
+	}
+	return result
+}
+
+func mean(values []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for _, v := range values {
+		sum.Add(sum, v)
+	}
+	return sum.Quo(sum, big.NewRat(int64(len(values)), 1))
+}
+
+func formatRat(r *big.Rat) json.Number {
+	f, _ := r.Float64()
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// AdjustmentBetween returns the cumulative split factor between two dates of a single code's series:
+// the product of AdjustmentFactor over the records dated after from up to and including to.
+// Multiplying a raw price on from by the result gives its to-equivalent (e.g. 0.5 across a 1:2 split).
+// It returns an error if from or to is not in prices, if from is after to, or if a factor in the range is
+// empty, unparsable, or not positive, naming the offending date instead of producing a zero or infinite ratio.
+func AdjustmentBetween(prices []StockPrice, from, to string) (json.Number, error) {
+	if from > to {
+		return "", fmt.Errorf("from date %s is after to date %s", from, to)
+	}
+	var hasFrom, hasTo bool
+	factor := big.NewRat(1, 1)
+	for _, p := range prices {
+		hasFrom = hasFrom || p.Date == from
+		hasTo = hasTo || p.Date == to
+		if p.Date <= from || p.Date > to {
+			continue
+		}
+		f, ok := new(big.Rat).SetString(p.AdjustmentFactor.String())
+		if !ok {
+			return "", fmt.Errorf("invalid adjustment factor %q on %s", p.AdjustmentFactor, p.Date)
+		}
+		if f.Sign() <= 0 {
+			return "", fmt.Errorf("adjustment factor %s on %s is not positive", p.AdjustmentFactor, p.Date)
+		}
+		factor.Mul(factor, f)
+	}
+	if !hasFrom {
+		return "", fmt.Errorf("date %s not found in prices", from)
+	}
+	if !hasTo {
+		return "", fmt.Errorf("date %s not found in prices", to)
+	}
+	return formatRat(factor), nil
+}
+
+// AdjustPrices recomputes the split-adjusted prices and volumes of prices from the raw values and the
+// running product of AdjustmentFactor, so that series fetched on different days (whose adjusted fields
+// may be on different bases) form one consistent adjusted series. prices may hold several codes; the result
+// is a new slice sorted by code and date, and prices is not modified.
+//
+// Each code is adjusted to the basis of its latest record, like the API: the adjusted price of a day is
+// its raw price times the product of the factors of all later records, and the adjusted volume is the raw
+// volume divided by that product. Adjusted fields are nil where the raw value is nil (e.g., non-trading
+// days). Like [AdjustmentBetween], it returns an error naming the code and date of every empty, unparsable,
+// or non-positive factor instead of guessing a value for it.
+//
+// Rounding: products are computed exactly. Where the cumulative factor is exactly 1 the raw prices and
+// volumes are copied unchanged; otherwise prices are rounded once to the nearest float64 and written in
+// the shortest decimal form (e.g., "1707.5"), and volumes to the nearest integer, halves away from zero.
+func AdjustPrices(prices []StockPrice) ([]StockPrice, error) {
+	adjusted := slices.Clone(prices)
+	slices.SortStableFunc(adjusted, func(a, b StockPrice) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), cmp.Compare(a.Date, b.Date))
+	})
+	factors := make([]*big.Rat, len(adjusted))
+	var errs []error
+	for i, p := range adjusted {
+		f, ok := new(big.Rat).SetString(p.AdjustmentFactor.String())
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("invalid adjustment factor %q for %s on %s", p.AdjustmentFactor, p.Code, p.Date))
+		case f.Sign() <= 0:
+			errs = append(errs, fmt.Errorf("adjustment factor %s for %s on %s is not positive", p.AdjustmentFactor, p.Code, p.Date))
+		}
+		factors[i] = f
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	one := big.NewRat(1, 1)
+	factor := new(big.Rat)
+	for i := len(adjusted) - 1; i >= 0; i-- {
+		p := &adjusted[i]
+		if i == len(adjusted)-1 || adjusted[i+1].Code != p.Code {
+			factor.SetInt64(1)
+		}
+		if factor.Cmp(one) == 0 {
+			p.AdjustedOpen, p.AdjustedHigh, p.AdjustedLow, p.AdjustedClose = copyNumber(p.Open), copyNumber(p.High), copyNumber(p.Low), copyNumber(p.Close)
+			p.AdjustedVolume = copyInt64(p.Volume)
+		} else {
+			p.AdjustedOpen, p.AdjustedHigh, p.AdjustedLow, p.AdjustedClose = scaleNumber(p.Open, factor), scaleNumber(p.High, factor), scaleNumber(p.Low, factor), scaleNumber(p.Close, factor)
+			p.AdjustedVolume = scaleVolume(p.Volume, factor)
+		}
+		factor.Mul(factor, factors[i])
+	}
+	return adjusted, nil
+}
+
+func copyNumber(n *json.Number) *json.Number {
+	if n == nil {
+		return nil
+	}
+	v := *n
+	return &v
+}
+
+func copyInt64(v *int64) *int64 {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// scaleNumber returns n times factor, or nil if n is nil or not a number.
+func scaleNumber(n *json.Number, factor *big.Rat) *json.Number {
+	if n == nil {
+		return nil
+	}
+	v, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return nil
+	}
+	scaled := formatRat(v.Mul(v, factor))
+	return &scaled
+}
+
+// scaleVolume returns v divided by factor, rounded to the nearest integer with halves away from zero.
+func scaleVolume(v *int64, factor *big.Rat) *int64 {
+	if v == nil {
+		return nil
+	}
+	q := new(big.Rat).Quo(new(big.Rat).SetInt64(*v), factor)
+	// Round half away from zero: trunc((2*num + sign*den) / (2*den)).
+	num := new(big.Int).Mul(q.Num(), big.NewInt(2))
+	num.Add(num, new(big.Int).Mul(q.Denom(), big.NewInt(int64(q.Sign()))))
+	rounded := num.Quo(num, new(big.Int).Mul(q.Denom(), big.NewInt(2))).Int64()
+	return &rounded
+}
@@ -0,0 +1,225 @@
+package jquants
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func number(s string) *json.Number {
+	n := json.Number(s)
+	return &n
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestSplitAdjusted(t *testing.T) {
+	prices := []StockPrice{
+		{
+			Date: "2024-01-04", Code: "13010",
+			Open: number("1000"), High: number("1100"), Low: number("900"), Close: number("1050"), Volume: int64Ptr(100),
+			AdjustedOpen: number("500"), AdjustedHigh: number("550"), AdjustedLow: number("450"), AdjustedClose: number("525"), AdjustedVolume: int64Ptr(200),
+		},
+		{Date: "2024-01-05", Code: "13010"},
+	}
+	raw, adjusted := SplitAdjusted(prices)
+	if len(raw) != 2 || len(adjusted) != 2 {
+		t.Fatalf("Unexpected length: raw=%d, adjusted=%d", len(raw), len(adjusted))
+	}
+	if !raw[0].HasData || raw[0].Close != 1050 || raw[0].Volume != 100 {
+		t.Errorf("Unexpected raw bar: %+v", raw[0])
+	}
+	if !adjusted[0].HasData || adjusted[0].Close != 525 || adjusted[0].Volume != 200 {
+		t.Errorf("Unexpected adjusted bar: %+v", adjusted[0])
+	}
+	if raw[1].HasData || adjusted[1].HasData {
+		t.Errorf("Expected no data for non-trading day: raw=%+v, adjusted=%+v", raw[1], adjusted[1])
+	}
+}
+
+func TestLimitStreaks(t *testing.T) {
+	prices := []StockPrice{
+		{Date: "2024-01-09", Code: "13010", UpperLimit: true},
+		{Date: "2024-01-04", Code: "13010", UpperLimit: true},
+		{Date: "2024-01-05", Code: "13010", UpperLimit: true},
+		{Date: "2024-01-10", Code: "13010"},
+		{Date: "2024-01-11", Code: "13010", LowerLimit: true},
+	}
+	streaks := LimitStreaks(prices)
+	if len(streaks) != 2 {
+		t.Fatalf("Unexpected number of streaks: %+v", streaks)
+	}
+	want := LimitStreak{Code: "13010", Direction: LimitUp, StartDate: "2024-01-04", EndDate: "2024-01-09", Days: 3}
+	if streaks[0] != want {
+		t.Errorf("Unexpected first streak: got %+v, want %+v", streaks[0], want)
+	}
+	if streaks[1].Direction != LimitDown || streaks[1].Days != 1 {
+		t.Errorf("Unexpected second streak: %+v", streaks[1])
+	}
+}
+
+func TestRollingStatistics(t *testing.T) {
+	prices := []StockPrice{
+		{Date: "2024-01-09", AdjustedClose: number("0.3")},
+		{Date: "2024-01-04", AdjustedClose: number("0.1")},
+		{Date: "2024-01-05", AdjustedClose: number("0.2")},
+		{Date: "2024-01-08"},
+	}
+	means := RollingMean(prices, 2)
+	want := []json.Number{"0.25", "", "0.15", ""}
+	if !slices.Equal(means, want) {
+		t.Errorf("Unexpected rolling mean: got %v, want %v", means, want)
+	}
+	stddevs := RollingStdDev(prices, 3)
+	if stddevs[0] != "0.1" || stddevs[1] != "" || stddevs[2] != "" {
+		t.Errorf("Unexpected rolling standard deviation: %v", stddevs)
+	}
+}
+
+func TestAdjustmentBetween(t *testing.T) {
+	prices := []StockPrice{
+		{Date: "2024-01-04", AdjustmentFactor: "1"},
+		{Date: "2024-01-05", AdjustmentFactor: "0.5"},
+		{Date: "2024-01-09", AdjustmentFactor: "1"},
+		{Date: "2024-01-10", AdjustmentFactor: "0.2"},
+	}
+	got, err := AdjustmentBetween(prices, "2024-01-04", "2024-01-10")
+	if err != nil {
+		t.Fatalf("Failed to compute adjustment: %v", err)
+	}
+	if got != "0.1" {
+		t.Errorf("Unexpected adjustment: got %s, want 0.1", got)
+	}
+	if got, err := AdjustmentBetween(prices, "2024-01-05", "2024-01-09"); err != nil || got != "1" {
+		t.Errorf("Unexpected adjustment without splits: %s, %v", got, err)
+	}
+	if got, err := AdjustmentBetween(prices, "2024-01-05", "2024-01-05"); err != nil || got != "1" {
+		t.Errorf("Unexpected adjustment for the same date: %s, %v", got, err)
+	}
+	if _, err := AdjustmentBetween(prices, "2024-01-04", "2024-01-11"); err == nil {
+		t.Error("Expected error for missing date")
+	}
+	for _, factor := range []json.Number{"0", "-1", "", "NaN"} {
+		invalid := []StockPrice{{Date: "2024-01-04", AdjustmentFactor: "1"}, {Date: "2024-01-05", AdjustmentFactor: factor}}
+		_, err := AdjustmentBetween(invalid, "2024-01-04", "2024-01-05")
+		if err == nil || !strings.Contains(err.Error(), "2024-01-05") {
+			t.Errorf("Expected error naming the date for factor %q, got %v", factor, err)
+		}
+	}
+}
+
+func TestAdjustPrices(t *testing.T) {
+	number := func(s string) *json.Number { n := json.Number(s); return &n }
+	integer := func(v int64) *int64 { return &v }
+	prices := []StockPrice{
+		{Date: "2024-01-09", Code: "72030", AdjustmentFactor: "1", Close: number("1700.0"), Volume: integer(200), AdjustedClose: number("9999")},
+		{Date: "2024-01-04", Code: "72030", AdjustmentFactor: "1", Close: number("3415.0"), Volume: integer(101)},
+		{Date: "2024-01-05", Code: "72030", AdjustmentFactor: "1"},
+		{Date: "2024-01-05", Code: "13010", AdjustmentFactor: "1", Close: number("3735.0"), Volume: integer(20400)},
+		{Date: "2024-01-04", Code: "13010", AdjustmentFactor: "1", Close: number("3700.0"), Volume: integer(101)},
+		{Date: "2024-01-08", Code: "72030", AdjustmentFactor: "0.4", Close: number("1690.5"), Volume: integer(101)},
+	}
+	got, err := AdjustPrices(prices)
+	if err != nil {
+		t.Fatalf("Failed to adjust prices: %v", err)
+	}
+	want := []struct {
+		key    string
+		close  string
+		volume int64
+	}{
+		{"13010 2024-01-04", "3700.0", 101},
+		{"13010 2024-01-05", "3735.0", 20400},
+		{"72030 2024-01-04", "1366", 253},
+		{"72030 2024-01-05", "", 0},
+		{"72030 2024-01-08", "1690.5", 101},
+		{"72030 2024-01-09", "1700.0", 200},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unexpected number of prices: %d", len(got))
+	}
+	for i, w := range want {
+		p := got[i]
+		if key := p.Code + " " + p.Date; key != w.key {
+			t.Errorf("Unexpected order at %d: got %s, want %s", i, key, w.key)
+			continue
+		}
+		if w.close == "" {
+			if p.AdjustedClose != nil || p.AdjustedVolume != nil {
+				t.Errorf("%s: expected nil adjusted fields for a non-trading day", w.key)
+			}
+			continue
+		}
+		if p.AdjustedClose == nil || p.AdjustedClose.String() != w.close {
+			t.Errorf("%s: unexpected adjusted close %v, want %s", w.key, p.AdjustedClose, w.close)
+		}
+		if p.AdjustedVolume == nil || *p.AdjustedVolume != w.volume {
+			t.Errorf("%s: unexpected adjusted volume %v, want %d", w.key, p.AdjustedVolume, w.volume)
+		}
+	}
+	if prices[0].AdjustedClose.String() != "9999" {
+		t.Error("Expected the input to be left unmodified")
+	}
+}
+
+func TestAdjustPrices_InvalidFactor(t *testing.T) {
+	prices := []StockPrice{
+		{Date: "2024-01-04", Code: "72030", AdjustmentFactor: "1"},
+		{Date: "2024-01-05", Code: "72030", AdjustmentFactor: ""},
+		{Date: "2024-01-09", Code: "72030", AdjustmentFactor: "0"},
+		{Date: "2024-01-10", Code: "13010", AdjustmentFactor: "abc"},
+	}
+	got, err := AdjustPrices(prices)
+	if got != nil {
+		t.Errorf("Expected no prices on error, got %d", len(got))
+	}
+	if err == nil {
+		t.Fatal("Expected an error for invalid factors")
+	}
+	for _, date := range []string{"2024-01-05", "2024-01-09", "2024-01-10"} {
+		if !strings.Contains(err.Error(), date) {
+			t.Errorf("Expected the error to name %s: %v", date, err)
+		}
+	}
+	if strings.Contains(err.Error(), "2024-01-04") {
+		t.Errorf("Expected the valid factor not to be reported: %v", err)
+	}
+}
+
+func TestRelativeVolume(t *testing.T) {
+	prices := []StockPrice{
+		{Date: "2024-01-04", AdjustedVolume: int64Ptr(100)},
+		{Date: "2024-01-05", AdjustedVolume: int64Ptr(0)},
+		{Date: "2024-01-09", AdjustedVolume: int64Ptr(300)},
+		{Date: "2024-01-10"},
+		{Date: "2024-01-11", AdjustedVolume: int64Ptr(400)},
+		{Date: "2024-01-12", AdjustedVolume: int64Ptr(0)},
+	}
+	got := RelativeVolume(prices, 2)
+	for _, i := range []int{0, 1, 2, 3} {
+		if !math.IsNaN(got[i]) {
+			t.Errorf("Expected NaN without a full baseline or volume at %d: %v", i, got)
+		}
+	}
+	if got[4] != 2 {
+		t.Errorf("Unexpected relative volume: got %v, want 2", got[4])
+	}
+	if !math.IsNaN(got[5]) {
+		t.Errorf("Expected NaN for a zero-volume day: %v", got[5])
+	}
+}
+
+func TestSeriesInfo(t *testing.T) {
+	prices := []StockPrice{{Date: "2024-03-01"}, {Date: "2024-01-04"}, {Date: "2024-02-01"}}
+	first, last, delisted := SeriesInfo(prices, "2024-03-29")
+	if first != "2024-01-04" || last != "2024-03-01" || !delisted {
+		t.Errorf("Unexpected series info: %s, %s, %v", first, last, delisted)
+	}
+	if _, _, delisted := SeriesInfo(prices, "2024-03-08"); delisted {
+		t.Error("Expected a short gap not to count as delisting")
+	}
+}
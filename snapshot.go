@@ -0,0 +1,134 @@
+package jquants
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// snapshotLookback is how many calendar days back LatestSnapshot searches for the latest data.
+const snapshotLookback = 35
+
+// snapshotShortSellingAttempts is the number of most recent trading days LatestSnapshot queries
+// for short selling data before giving up.
+const snapshotShortSellingAttempts = 5
+
+// Snapshot is the latest available data for a set of codes, as returned by [Client.LatestSnapshot].
+// A dataset that failed to load is left empty and its error is recorded in Errors.
+type Snapshot struct {
+	// Date is the JST date on which the snapshot was taken, in YYYY-MM-DD format.
+	Date string
+	// Calendar is the trading calendar entry for Date (nil if unavailable).
+	Calendar *TradingCalendar
+	// Prices holds the latest daily price of each code, keyed by code.
+	Prices map[string]StockPrice
+	// Margin holds the latest weekly margin trading outstanding of each code, keyed by code.
+	Margin map[string]MarginTradingOutstanding
+	// ShortSelling holds the per-sector short selling values of the latest trading day with data.
+	ShortSelling []ShortSellingValue
+	// Errors maps a dataset name ("calendar", "prices", "margin", "short_selling") to the error that
+	// prevented it from loading fully. Codes rejected by NormalizeCodes are reported under "codes".
+	Errors map[string]error
+}
+
+// LatestSnapshot concurrently fetches the latest prices and margin balances for codes, the latest
+// short selling data, and today's calendar status, and combines them into a Snapshot.
+// Datasets fail independently: the snapshot is always returned, and the returned error joins the errors
+// recorded in Snapshot.Errors (nil if every dataset loaded). codes are cleaned with NormalizeCodes.
+func (c *Client) LatestSnapshot(ctx context.Context, codes []string) (*Snapshot, error) {
+	codes, invalid := NormalizeCodes(codes)
+	now := time.Now().In(jst)
+	from := now.AddDate(0, 0, -snapshotLookback).Format(dateLayout)
+	to := now.Format(dateLayout)
+	s := &Snapshot{
+		Date:   to,
+		Prices: make(map[string]StockPrice, len(codes)),
+		Margin: make(map[string]MarginTradingOutstanding, len(codes)),
+		Errors: make(map[string]error),
+	}
+	if invalid != nil {
+		s.Errors["codes"] = invalid
+	}
+	var mu sync.Mutex
+	fail := func(dataset string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		s.Errors[dataset] = err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		entries, err := c.TradingCalendar(ctx, TradingCalendarRequest{From: &from, To: &to})
+		if err != nil {
+			fail("calendar", err)
+			fail("short_selling", fmt.Errorf("failed to get trading calendar: %w", err))
+			return
+		}
+		calendar := NewCalendar(entries)
+		if day, ok := calendar.Day(to); ok {
+			s.Calendar = &day
+		}
+		values, err := c.latestShortSelling(ctx, calendar.TradingDays(from, to))
+		if err != nil {
+			fail("short_selling", err)
+		}
+		s.ShortSelling = values
+	}()
+	go func() {
+		defer wg.Done()
+		prices, err := fetchBatch(ctx, c, codes, func(ctx context.Context, code string) ([]StockPrice, error) {
+			return c.RecentStockPrices(ctx, code, 1)
+		})
+		if err != nil {
+			fail("prices", err)
+		}
+		for code, p := range prices {
+			if len(p) > 0 {
+				s.Prices[code] = p[len(p)-1]
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		margin, err := fetchBatch(ctx, c, codes, func(ctx context.Context, code string) ([]MarginTradingOutstanding, error) {
+			return c.MarginTradingOutstanding(ctx, MarginTradingOutstandingRequest{Code: &code, From: &from, To: &to})
+		})
+		if err != nil {
+			fail("margin", err)
+		}
+		for code, m := range margin {
+			if len(m) > 0 {
+				s.Margin[code] = slices.MaxFunc(m, func(a, b MarginTradingOutstanding) int { return cmp.Compare(a.Date, b.Date) })
+			}
+		}
+	}()
+	wg.Wait()
+
+	errs := make([]error, 0, len(s.Errors))
+	for _, dataset := range []string{"codes", "calendar", "prices", "margin", "short_selling"} {
+		if err, ok := s.Errors[dataset]; ok {
+			errs = append(errs, fmt.Errorf("%s: %w", dataset, err))
+		}
+	}
+	return s, errors.Join(errs...)
+}
+
+// latestShortSelling returns the short selling values of the most recent of tradingDays that has data.
+func (c *Client) latestShortSelling(ctx context.Context, tradingDays []string) ([]ShortSellingValue, error) {
+	for i := len(tradingDays) - 1; i >= max(0, len(tradingDays)-snapshotShortSellingAttempts); i-- {
+		values, err := c.ShortSellingValue(ctx, ShortSellingValueRequest{Date: &tradingDays[i]})
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			return values, nil
+		}
+	}
+	return nil, errors.New("no short selling data found for recent trading days")
+}
@@ -0,0 +1,16 @@
+package jquants
+
+import (
+	"testing"
+)
+
+func TestClient_LatestSnapshot(t *testing.T) {
+	client := setupClient(t)
+	snapshot, err := client.LatestSnapshot(t.Context(), []string{"13010", "72030"})
+	if err != nil {
+		t.Errorf("Failed to get latest snapshot: %s", err)
+	}
+	if len(snapshot.Prices) == 0 {
+		t.Error("Empty prices in snapshot")
+	}
+}
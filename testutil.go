@@ -7,7 +7,7 @@ import (
 
 func setupClient(t *testing.T) *Client {
 	t.Helper()
-	apiKey, ok := os.LookupEnv("J_QUANTS_API_KEY")
+	apiKey, ok := os.LookupEnv(APIKeyEnv)
 	if !ok {
 		t.Fatal("J_QUANTS_API_KEY environment variable is not set")
 	}
@@ -0,0 +1,99 @@
+// Package tracing adds OpenTelemetry instrumentation to the HTTP requests sent by a jquants.Client.
+//
+// It is a separate module so that the core jquants module stays free of the
+// OpenTelemetry dependency for users who do not need it. Install it with jquants.WithMiddleware:
+//
+//	client := jquants.NewClient(jquants.BaseURL, apiKey,
+//	    jquants.WithMiddleware(tracing.Middleware(otel.GetTracerProvider())))
+//
+// or, to trace a client shared outside jquants, with jquants.WithHTTPClient and NewHTTPClient.
+package tracing
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/s-shiga/jquants-go/v2"
+)
+
+const instrumentationName = "github.com/s-shiga/jquants-go/v2/tracing"
+
+// NewHTTPClient returns a copy of base whose requests are traced with tp.
+// Each request gets a client span named after the endpoint path (e.g., "/equities/bars/daily")
+// that records the HTTP status code and any error, and the trace context is injected into the
+// outgoing headers with the global propagator (see otel.SetTextMapPropagator).
+// A nil base uses http.DefaultClient.
+func NewHTTPClient(base *http.Client, tp trace.TracerProvider) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	client := *base
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.Transport = Middleware(tp)(transport)
+	return &client
+}
+
+// Middleware returns a jquants.WithMiddleware middleware that traces requests with tp, like NewHTTPClient.
+func Middleware(tp trace.TracerProvider) func(http.RoundTripper) http.RoundTripper {
+	tracer := tp.Tracer(instrumentationName, trace.WithInstrumentationVersion(jquants.Version))
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &roundTripper{next: next, tracer: tracer}
+	}
+}
+
+type roundTripper struct {
+	next   http.RoundTripper
+	tracer trace.Tracer
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := rt.tracer.Start(req.Context(), endpoint(req.URL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// basePath is the path prefix of jquants.BaseURL (e.g., "/v2"), trimmed from span names.
+var basePath = func() string {
+	u, err := url.Parse(jquants.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}()
+
+// endpoint returns the API endpoint path of u without the API version prefix.
+func endpoint(u *url.URL) string {
+	if path := strings.TrimPrefix(u.Path, basePath); path != "" {
+		return path
+	}
+	return u.Path
+}
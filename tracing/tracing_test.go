@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := NewHTTPClient(nil, tp)
+
+	resp, err := client.Get(server.URL + "/v2/equities/bars/daily?code=13010")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Unexpected number of spans: %d", len(spans))
+	}
+	if spans[0].Name() != "/equities/bars/daily" {
+		t.Errorf("Unexpected span name: %s", spans[0].Name())
+	}
+	found := false
+	for _, attr := range spans[0].Attributes() {
+		if attr == attribute.Int("http.response.status_code", http.StatusForbidden) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Status code attribute not recorded: %v", spans[0].Attributes())
+	}
+	if traceparent == "" {
+		t.Error("Trace context was not propagated")
+	}
+}
@@ -0,0 +1,45 @@
+package jquants
+
+import "strconv"
+
+// WarningCode identifies the kind of a [Warning].
+type WarningCode string
+
+const (
+	// WarningMalformedRecords means records on a page failed to decode and were skipped (see [WithSkipMalformedRecords]).
+	WarningMalformedRecords WarningCode = "malformed_records"
+	// WarningEmptyPage means a page contained no records but pointed to a further page.
+	WarningEmptyPage WarningCode = "empty_page"
+	// WarningRetry means a request failed with a retryable error and is being retried.
+	WarningRetry WarningCode = "retry"
+	// WarningTruncatedRange means part of the requested date range is outside the subscription plan and only
+	// the covered part was fetched.
+	WarningTruncatedRange WarningCode = "truncated_range"
+)
+
+// Warning is a non-fatal issue encountered while fetching data.
+// Warnings do not stop a fetch; they are reported to the handler set with [WithWarningHandler].
+type Warning struct {
+	// Code identifies the kind of warning.
+	Code WarningCode
+	// Message is a human-readable description.
+	Message string
+	// Context holds additional details such as the page number or pagination key.
+	Context map[string]string
+}
+
+// warn reports a pagination warning to the client's warning handler, if any.
+func (c *Client) warn(code WarningCode, message string, page int, paginationKey *string) {
+	context := map[string]string{"page": strconv.Itoa(page)}
+	if paginationKey != nil {
+		context["pagination_key"] = *paginationKey
+	}
+	c.report(Warning{Code: code, Message: message, Context: context})
+}
+
+// report passes w to the client's warning handler, if any.
+func (c *Client) report(w Warning) {
+	if c.warningHandler != nil {
+		c.warningHandler(w)
+	}
+}